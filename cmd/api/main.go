@@ -12,13 +12,18 @@ import (
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/insan1a/exile/internal/config"
+	"github.com/insan1a/exile/internal/lib/personname"
 	"github.com/insan1a/exile/internal/lib/sl"
+	"github.com/insan1a/exile/internal/lib/validator"
 	"github.com/insan1a/exile/internal/log"
 	"github.com/insan1a/exile/internal/server/graphql/person"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/delete"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/get"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/list"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/request"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/save"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/search"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/stats"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/update"
 	"github.com/insan1a/exile/internal/server/middleware"
 	"github.com/insan1a/exile/internal/service/people"
@@ -30,11 +35,21 @@ func main() {
 
 	log := log.New(cfg.Env, os.Stderr)
 
-	svc, err := people.New(
+	nameRules, err := personname.NewRules(cfg.NameScripts, cfg.NamePunctuation)
+	failedOnError("failed to load name rules", err)
+	validator.SetNameRules(nameRules)
+
+	opts := []people.Option{
 		people.WithRedisCache(cfg.CacheURL),
 		people.WithPostgresPersonStorage(cfg.DatabaseURL),
+		people.WithPostgresRequestStorage(cfg.DatabaseURL),
 		people.WithKafkaProducer(&cfg.KafkaMap, cfg.Topic),
-	)
+	}
+	if cfg.ReplyTopic != "" {
+		opts = append(opts, people.WithKafkaReplyConsumer(&cfg.ReplyKafkaMap, cfg.ReplyTopic, cfg.ReplyTimeout))
+	}
+
+	svc, err := people.New(opts...)
 	failedOnError("failed to create people service", err)
 
 	mux := chi.NewMux()
@@ -43,7 +58,10 @@ func main() {
 	mux.Use(chimiddleware.Recoverer)
 	mux.Route("/person", func(r chi.Router) {
 		r.Post("/", save.New(log, svc))
-		r.Get("/", list.New(log, svc))
+		r.Get("/", list.New(log, svc, svc))
+		r.Get("/search", search.New(log, svc))
+		r.Get("/stats", stats.New(log, svc))
+		r.Get("/requests/{id}", request.New(log, svc))
 
 		r.Route("/{id}", func(r chi.Router) {
 			r.Delete("/", delete.New(log, svc))
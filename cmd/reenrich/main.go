@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/insan1a/exile/internal/client"
+	"github.com/insan1a/exile/internal/config"
+	"github.com/insan1a/exile/internal/lib/sl"
+	"github.com/insan1a/exile/internal/log"
+	"github.com/insan1a/exile/internal/service/reenrich"
+	"github.com/insan1a/exile/internal/storage"
+	"github.com/insan1a/exile/internal/storage/cache"
+	"github.com/insan1a/exile/internal/storage/cache/memory"
+	"github.com/insan1a/exile/internal/storage/cache/redis"
+)
+
+func main() {
+	cfg, err := config.LoadReenrichConfig()
+	failedOnError("failed to read config", err)
+
+	log := log.New(cfg.Env, os.Stderr)
+
+	log.Info("config loaded", slog.Any("cfg", cfg))
+
+	enrichmentCache, err := newEnrichmentCache(cfg, log)
+	failedOnError("failed to create enrichment cache", err)
+
+	httpClient := &http.Client{Timeout: cfg.ProvidersTimeout}
+	apiOpts := func(baseURL string) []client.Option {
+		return []client.Option{
+			client.WithBaseURL(baseURL),
+			client.WithAPIKey(cfg.ProvidersAPIKey),
+			client.WithHTTPClient(httpClient),
+		}
+	}
+
+	providerOpts := []client.ProviderOption{
+		client.WithAPI(client.ProviderAgify, apiOpts(cfg.AgifyURL)...),
+		client.WithAPI(client.ProviderGenderize, apiOpts(cfg.GenderizeURL)...),
+		client.WithAPI(client.ProviderNationalize, apiOpts(cfg.NationalizeURL)...),
+		client.WithCache(enrichmentCache, cfg.EnrichmentCacheTTL, cfg.EnrichmentCacheNegativeTTL),
+	}
+	if cfg.DatasetPath != "" {
+		dataset, err := client.LoadDataset(cfg.DatasetPath)
+		failedOnError("failed to load dataset", err)
+
+		datasetOpt, err := dataset.Option(cfg.DatasetMode)
+		failedOnError("failed to use dataset", err)
+		providerOpts = append(providerOpts, datasetOpt)
+
+		go dataset.Watch(context.Background(), cfg.DatasetReloadInterval, func(err error) {
+			log.Error("failed to reload dataset", sl.Err(err))
+		})
+	}
+
+	providers := client.Providers(providerOpts...)
+
+	enrichers, err := client.Select(providers, cfg.Enrichers)
+	failedOnError("failed to select enrichers", err)
+
+	registry, err := client.NewRegistry(enrichers...)
+	failedOnError("failed to create enricher registry", err)
+
+	svc, err := reenrich.New(
+		reenrich.WithJob(cfg.Job),
+		reenrich.WithBatch(cfg.BatchSize, cfg.Timeout),
+		reenrich.WithPostgresStorage(cfg.DatabaseURL),
+		reenrich.WithEnricher(registry),
+		reenrich.WithCache(enrichmentCache, cfg.Enrichers...),
+	)
+	failedOnError("failed to create service", err)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Info("the re-enrichment is running", slog.String("job", cfg.Job))
+
+	updated, err := svc.Run(ctx, time.Now().Add(-cfg.StaleAfter))
+	switch {
+	case err == nil:
+		log.Info("the re-enrichment is finished", slog.Int("updated", updated))
+	case errors.Is(err, context.Canceled):
+		log.Info("the re-enrichment is interrupted, it is resumed on the next run", slog.Int("updated", updated))
+	default:
+		log.Error("failed to re-enrich people", slog.Int("updated", updated), sl.Err(err))
+		os.Exit(1)
+	}
+}
+
+// newEnrichmentCache returns the redis cache if it is configured and available,
+// otherwise the in-memory LRU cache.
+func newEnrichmentCache(cfg *config.ReenrichConfig, log *slog.Logger) (cache.Cache, error) {
+	if cfg.CacheURL != "" {
+		rc, err := storage.NewRedisClient(cfg.CacheURL)
+		if err == nil {
+			return redis.New(rc)
+		}
+
+		log.Warn("failed to connect to redis, the in-memory cache is used", sl.Err(err))
+	}
+
+	return memory.New(cfg.EnrichmentCacheSize)
+}
+
+func failedOnError(msg string, err error) {
+	if err != nil {
+		fmt.Println(msg, fmt.Sprintf("error: %v", err))
+		os.Exit(1)
+	}
+}
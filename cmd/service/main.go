@@ -3,20 +3,30 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/insan1a/exile/internal/client"
 	"github.com/insan1a/exile/internal/config"
+	"github.com/insan1a/exile/internal/lib/personname"
+	"github.com/insan1a/exile/internal/lib/retry"
 	"github.com/insan1a/exile/internal/lib/sl"
+	"github.com/insan1a/exile/internal/lib/validator"
 	"github.com/insan1a/exile/internal/log"
 	"github.com/insan1a/exile/internal/service/person"
+	"github.com/insan1a/exile/internal/service/reenrich"
 	"github.com/insan1a/exile/internal/storage"
 	brokerkafka "github.com/insan1a/exile/internal/storage/broker/kafka"
+	"github.com/insan1a/exile/internal/storage/cache"
+	"github.com/insan1a/exile/internal/storage/cache/memory"
+	"github.com/insan1a/exile/internal/storage/cache/redis"
 )
 
 func main() {
@@ -25,26 +35,115 @@ func main() {
 
 	log := log.New(cfg.Env, os.Stderr)
 
+	nameRules, err := personname.NewRules(cfg.NameScripts, cfg.NamePunctuation)
+	failedOnError("failed to load name rules", err)
+	validator.SetNameRules(nameRules)
+
 	log.Info("config loaded", slog.Any("cfg", cfg))
 
-	kc, err := storage.NewKafkaConsumer(&cfg.KafkaMap, cfg.Topics)
+	topics := cfg.Topics
+	if cfg.RetryTopic != "" {
+		topics = append(topics, cfg.RetryTopic)
+	}
+
+	kc, err := storage.NewKafkaConsumer(&cfg.KafkaMap, topics)
 	failedOnError("failed to create kafka consumer", err)
 
 	kp, err := storage.NewKafkaProducer(&cfg.KafkaMap)
 	failedOnError("failed to create kafka producer", err)
 
-	svc, err := person.New(
-		person.WithConsumer(brokerkafka.NewConsumer(kc)),
+	if cfg.MetricsPort != "" {
+		go func() {
+			if err := http.ListenAndServe(":"+cfg.MetricsPort, expvar.Handler()); err != nil {
+				log.Error("failed to serve metrics", sl.Err(err))
+			}
+		}()
+	}
+
+	enrichmentCache, err := newEnrichmentCache(cfg, log)
+	failedOnError("failed to create enrichment cache", err)
+
+	httpClient := &http.Client{Timeout: cfg.ProvidersTimeout}
+	apiOpts := func(baseURL string) []client.Option {
+		return []client.Option{
+			client.WithBaseURL(baseURL),
+			client.WithAPIKey(cfg.ProvidersAPIKey),
+			client.WithHTTPClient(httpClient),
+		}
+	}
+
+	providerOpts := []client.ProviderOption{
+		client.WithAPI(client.ProviderAgify, apiOpts(cfg.AgifyURL)...),
+		client.WithAPI(client.ProviderGenderize, apiOpts(cfg.GenderizeURL)...),
+		client.WithAPI(client.ProviderNationalize, apiOpts(cfg.NationalizeURL)...),
+		client.WithCache(enrichmentCache, cfg.EnrichmentCacheTTL, cfg.EnrichmentCacheNegativeTTL),
+	}
+	if cfg.DatasetPath != "" {
+		dataset, err := client.LoadDataset(cfg.DatasetPath)
+		failedOnError("failed to load dataset", err)
+
+		datasetOpt, err := dataset.Option(cfg.DatasetMode)
+		failedOnError("failed to use dataset", err)
+		providerOpts = append(providerOpts, datasetOpt)
+
+		go dataset.Watch(context.Background(), cfg.DatasetReloadInterval, func(err error) {
+			log.Error("failed to reload dataset", sl.Err(err))
+		})
+	}
+
+	providers := client.Providers(providerOpts...)
+
+	enrichers, err := client.Select(providers, cfg.Enrichers)
+	failedOnError("failed to select enrichers", err)
+
+	registry, err := client.NewRegistry(enrichers...)
+	failedOnError("failed to create enricher registry", err)
+
+	opts := []person.Option{
+		person.WithConsumer(brokerkafka.NewConsumer(kc, brokerkafka.WithManualCommit())),
 		person.WithProducer((brokerkafka.NewProducer(kp, cfg.Topic)), cfg.Topic),
 		person.WithTimeout(cfg.Timeout),
+		person.WithBatch(cfg.BatchSize, cfg.BatchWait),
+		person.WithRetry(retry.Policy{
+			Attempts: cfg.RetryAttempts,
+			Initial:  cfg.RetryBackoff,
+			Max:      cfg.RetryMaxBackoff,
+		}),
 		person.WithPostgresPeopleStorage(cfg.DatabaseURL),
-		person.WithAgifyClient(client.NewAgeFetcher()),
-		person.WithGenderizeClient(client.NewGenderFetcher()),
-		person.WithNationalizeClient(client.NewNationalityFetcher()),
-	)
+		person.WithPostgresRequestStorage(cfg.DatabaseURL),
+		person.WithEnricher(registry),
+		person.WithPolicies(policies(cfg.EnrichmentPolicies)),
+	}
+	if peopleCache := newPeopleCache(cfg, log); peopleCache != nil {
+		opts = append(opts, person.WithPeopleCache(peopleCache))
+	}
+	if cfg.ReplyTopic != "" {
+		opts = append(opts, person.WithReplyProducer(brokerkafka.NewProducer(kp, cfg.ReplyTopic)))
+	}
+	if cfg.RetryTopic != "" {
+		opts = append(opts, person.WithRetryProducer(brokerkafka.NewProducer(kp, cfg.RetryTopic, brokerkafka.WithSyncDelivery()), retry.Policy{
+			Attempts: cfg.RetryTopicAttempts,
+			Initial:  cfg.RetryTopicDelay,
+			Max:      cfg.RetryTopicMaxDelay,
+		}))
+	}
+
+	svc, err := person.New(opts...)
 
 	failedOnError("failed to create service", err)
 
+	if optional := optionalProviders(cfg.EnrichmentPolicies); cfg.ReenrichInterval > 0 && len(optional) > 0 {
+		reenricher, err := reenrich.New(
+			reenrich.WithBatch(cfg.ReenrichBatchSize, reenrich.DefaultTimeout),
+			reenrich.WithPostgresStorage(cfg.DatabaseURL),
+			reenrich.WithEnricher(registry),
+			reenrich.WithCache(enrichmentCache, cfg.Enrichers...),
+		)
+		failedOnError("failed to create re-enrichment service", err)
+
+		go reenrichPartial(log, reenricher, optional, cfg.ReenrichInterval)
+	}
+
 	log.Info("the service is running")
 
 	exitCh := make(chan os.Signal, 1)
@@ -60,30 +159,127 @@ run:
 			log.Info("the service is stopped")
 			break run
 		default:
-			if res, err := svc.Save(context.Background()); err != nil {
-				switch err := err.(type) {
-				case kafka.Error:
-					if err.Code() == kafka.ErrTimedOut {
-						continue
-					}
-					log.Error("failed to read message", sl.Err(err))
-				default:
-					if errors.Is(err, person.ErrMessageFromat) || errors.Is(err, person.ErrMessageValidation) {
-						if err := svc.SendErrMessage(res, err.Error()); err != nil {
-							log.Error("failed to send error message", sl.Err(err))
-						}
-						log.Info("the invalid message was send", slog.Any("message", res), sl.Err(err))
-					} else {
-						log.Error("failed to save person", sl.Err(err))
-					}
-				}
-			} else {
-				log.Info("the person successfully saved", slog.Any("person", res))
+			results, err := svc.SaveBatch(context.Background())
+			if err != nil && !isTimeout(err) && !errors.Is(err, person.ErrPaused) {
+				log.Error("failed to consume messages", sl.Err(err))
+			}
+
+			for _, res := range results {
+				handleResult(log, svc, res)
 			}
 		}
 	}
 }
 
+// handleResult logs the result of processing of the message
+// and sends the failed message to the failure topic.
+func handleResult(log *slog.Logger, svc *person.Service, res person.Result) {
+	switch {
+	case res.Err == nil:
+		log.Info("the person successfully saved", slog.String("person", string(res.Message.Value)))
+	case errors.Is(res.Err, person.ErrPostponed):
+		log.Warn("the message is postponed", slog.String("request_id", res.Message.Key), sl.Err(res.Err))
+	case errors.Is(res.Err, person.ErrRetryScheduled):
+		log.Warn("the message is sent to the retry topic", slog.String("request_id", res.Message.Key), sl.Err(res.Err))
+	case errors.Is(res.Err, person.ErrRetryNotScheduled):
+		log.Error("failed to send the message to the retry topic", slog.String("request_id", res.Message.Key), sl.Err(res.Err))
+	case errors.Is(res.Err, person.ErrReport):
+		log.Error("failed to report the result", slog.String("request_id", res.Message.Key), sl.Err(res.Err))
+	case errors.Is(res.Err, person.ErrMessageFromat) || errors.Is(res.Err, person.ErrMessageValidation):
+		if err := svc.SendErrMessage(context.Background(), res.Message, res.Err.Error()); err != nil {
+			log.Error("failed to send error message", sl.Err(err))
+		}
+		log.Info("the invalid message was send", slog.String("message", string(res.Message.Value)), sl.Err(res.Err))
+	default:
+		if err := svc.SendErrMessage(context.Background(), res.Message, res.Err.Error()); err != nil {
+			log.Error("failed to send error message", sl.Err(err))
+		}
+		log.Error("failed to save person", slog.String("message", string(res.Message.Value)), sl.Err(res.Err))
+	}
+}
+
+// reenrichPartial enriches again the people stored without the fields
+// of the optional providers and not tried within the interval every interval.
+func reenrichPartial(log *slog.Logger, svc *reenrich.Service, providers []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		updated, err := svc.RunPartial(context.Background(), providers, time.Now().Add(-interval))
+		if err != nil {
+			log.Error("failed to re-enrich people", slog.Int("updated", updated), sl.Err(err))
+			continue
+		}
+
+		log.Info("the people are re-enriched", slog.Int("updated", updated))
+	}
+}
+
+// optionalProviders returns the providers with the optional policy.
+func optionalProviders(cfg map[string]string) []string {
+	var providers []string
+	for provider, policy := range cfg {
+		if person.Policy(policy) == person.PolicyOptional {
+			providers = append(providers, provider)
+		}
+	}
+
+	return providers
+}
+
+// policies converts the configured policies of the providers.
+func policies(cfg map[string]string) map[string]person.Policy {
+	result := make(map[string]person.Policy, len(cfg))
+	for provider, policy := range cfg {
+		result[provider] = person.Policy(policy)
+	}
+
+	return result
+}
+
+// isTimeout reports whether the error is the timeout of the kafka consumer.
+func isTimeout(err error) bool {
+	var kafkaErr kafka.Error
+	return errors.As(err, &kafkaErr) && kafkaErr.Code() == kafka.ErrTimedOut
+}
+
+// newEnrichmentCache returns the redis cache if it is configured and available,
+// otherwise the in-memory LRU cache.
+func newEnrichmentCache(cfg *config.ServiceConfig, log *slog.Logger) (cache.Cache, error) {
+	if cfg.CacheURL != "" {
+		rc, err := storage.NewRedisClient(cfg.CacheURL)
+		if err == nil {
+			return redis.New(rc)
+		}
+
+		log.Warn("failed to connect to redis, the in-memory cache is used", sl.Err(err))
+	}
+
+	return memory.New(cfg.EnrichmentCacheSize)
+}
+
+// newPeopleCache returns the redis cache of the people shared with the API to invalidate,
+// nil is returned if it is not configured or available.
+func newPeopleCache(cfg *config.ServiceConfig, log *slog.Logger) cache.Cache {
+	if cfg.CacheURL == "" {
+		return nil
+	}
+
+	rc, err := storage.NewRedisClient(cfg.CacheURL)
+	if err != nil {
+		log.Error("failed to connect to redis, the cached people are not invalidated", sl.Err(err))
+		return nil
+	}
+
+	c, err := redis.New(rc)
+	if err != nil {
+		log.Error("failed to create people cache", sl.Err(err))
+		return nil
+	}
+
+	return c
+}
+
 func failedOnError(msg string, err error) {
 	if err != nil {
 		fmt.Println(msg, fmt.Sprintf("error: %v", err))
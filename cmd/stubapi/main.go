@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/insan1a/exile/internal/config"
+	"github.com/insan1a/exile/internal/lib/sl"
+	"github.com/insan1a/exile/internal/lib/stubapi"
+	"github.com/insan1a/exile/internal/log"
+)
+
+func main() {
+	cfg, err := config.LoadStubAPIConfig()
+	failedOnError("failed to load config", err)
+
+	log := log.New(cfg.Env, os.Stderr)
+
+	stub := stubapi.New(
+		stubapi.WithLimit(cfg.Limit, cfg.Window),
+		stubapi.WithLatency(cfg.Latency),
+		stubapi.WithFailureRate(cfg.FailureRate),
+		stubapi.WithAPIKey(cfg.APIKey),
+	)
+
+	log.Info("the stub api starting",
+		slog.String("port", cfg.Port),
+		slog.String("agify", stubapi.PathAgify),
+		slog.String("genderize", stubapi.PathGenderize),
+		slog.String("nationalize", stubapi.PathNationalize),
+	)
+
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: stub.Handler(),
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			failedOnError("failed to start the server", err)
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	<-sigCh
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	failedOnError("failed to shutdown the server", srv.Shutdown(shutdownCtx))
+
+	log.Info("the stub api stopped")
+}
+
+func failedOnError(msg string, err error) {
+	if err != nil {
+		slog.Error(msg, sl.Err(err))
+		os.Exit(1)
+	}
+}
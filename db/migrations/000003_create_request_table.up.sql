@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS request (
+    id uuid NOT NULL,
+    status varchar(10) DEFAULT 'pending' NOT NULL,
+    person_id uuid,
+    error text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT request_pk PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS request_status_idx ON request (status);
@@ -0,0 +1,8 @@
+ALTER TABLE person
+    ADD COLUMN IF NOT EXISTS age_count int,
+    ADD COLUMN IF NOT EXISTS gender_probability double precision,
+    ADD COLUMN IF NOT EXISTS gender_count int,
+    ADD COLUMN IF NOT EXISTS nationality_probability double precision,
+    ADD COLUMN IF NOT EXISTS nationality_count int;
+CREATE INDEX IF NOT EXISTS person_gender_probability_idx ON person (gender_probability);
+CREATE INDEX IF NOT EXISTS person_nationality_probability_idx ON person (nationality_probability);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS person_nationality (
+    person_id uuid NOT NULL,
+    country_id varchar(2) NOT NULL,
+    probability double precision DEFAULT 0 NOT NULL,
+    CONSTRAINT person_nationality_pk PRIMARY KEY (person_id, country_id),
+    CONSTRAINT person_nationality_person_fk FOREIGN KEY (person_id) REFERENCES person (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS person_nationality_country_idx ON person_nationality (country_id, probability DESC);
+INSERT INTO person_nationality (person_id, country_id, probability)
+SELECT id, nationality, COALESCE(nationality_probability, 0)
+FROM person
+WHERE nationality IS NOT NULL AND nationality <> ''
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS person_enrichment_status_idx;
+ALTER TABLE person
+    DROP COLUMN IF EXISTS enrichment_status;
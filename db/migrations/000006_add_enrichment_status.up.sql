@@ -0,0 +1,4 @@
+ALTER TABLE person
+    ADD COLUMN IF NOT EXISTS enrichment_status jsonb DEFAULT '{}'::jsonb NOT NULL;
+CREATE INDEX IF NOT EXISTS person_enrichment_status_idx ON person USING gin (enrichment_status)
+    WHERE enrichment_status <> '{}'::jsonb;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reenrich_job;
+DROP INDEX IF EXISTS person_enriched_at_idx;
+ALTER TABLE person
+    DROP COLUMN IF EXISTS enriched_at;
@@ -0,0 +1,12 @@
+ALTER TABLE person
+    ADD COLUMN IF NOT EXISTS enriched_at timestamp with time zone DEFAULT 'epoch' NOT NULL;
+ALTER TABLE person
+    ALTER COLUMN enriched_at SET DEFAULT now();
+CREATE INDEX IF NOT EXISTS person_enriched_at_idx ON person (enriched_at);
+CREATE TABLE IF NOT EXISTS reenrich_job (
+    name varchar(100) NOT NULL,
+    cutoff timestamp with time zone NOT NULL,
+    last_id uuid,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT reenrich_job_pk PRIMARY KEY (name)
+);
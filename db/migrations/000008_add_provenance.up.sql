@@ -0,0 +1,2 @@
+ALTER TABLE person
+    ADD COLUMN IF NOT EXISTS provenance jsonb DEFAULT '{}'::jsonb NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS person_patronymic_trgm_idx;
+DROP INDEX IF EXISTS person_surname_trgm_idx;
+DROP INDEX IF EXISTS person_name_trgm_idx;
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS person_name_trgm_idx ON person USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS person_surname_trgm_idx ON person USING gin (surname gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS person_patronymic_trgm_idx ON person USING gin (patronymic gin_trgm_ops);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS person_search_name_trgm_idx;
+ALTER TABLE person
+    DROP COLUMN IF EXISTS search_name;
+DROP FUNCTION IF EXISTS person_translit(text);
+DROP EXTENSION IF EXISTS fuzzystrmatch;
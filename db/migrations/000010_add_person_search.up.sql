@@ -0,0 +1,17 @@
+CREATE EXTENSION IF NOT EXISTS fuzzystrmatch;
+CREATE OR REPLACE FUNCTION person_translit(s text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$
+    SELECT translate(
+        replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(
+            lower(s),
+            'щ', 'shch'), 'ш', 'sh'), 'ч', 'ch'), 'ж', 'zh'), 'ц', 'ts'), 'х', 'kh'),
+            'ю', 'yu'), 'я', 'ya'), 'ї', 'yi'), 'є', 'ye'), 'й', 'y'),
+        'абвгґдеёзиіклмнопрстуфыэъь',
+        'abvggdeeziiklmnoprstufye'
+    )
+    $$;
+ALTER TABLE person
+    ADD COLUMN IF NOT EXISTS search_name text
+    GENERATED ALWAYS AS (person_translit(name || ' ' || surname || ' ' || COALESCE(patronymic, ''))) STORED;
+CREATE INDEX IF NOT EXISTS person_search_name_trgm_idx ON person USING gin (search_name gin_trgm_ops);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS person_created_at_idx;
+ALTER TABLE person
+    DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,3 @@
+ALTER TABLE person
+    ADD COLUMN IF NOT EXISTS created_at timestamptz DEFAULT now() NOT NULL;
+CREATE INDEX IF NOT EXISTS person_created_at_idx ON person (created_at);
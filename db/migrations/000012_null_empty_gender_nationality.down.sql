@@ -0,0 +1,2 @@
+-- the unknown gender and nationality are stored as NULL since the people are created,
+-- the empty values are not restored.
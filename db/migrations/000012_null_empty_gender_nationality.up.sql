@@ -0,0 +1,2 @@
+UPDATE person SET gender = NULL WHERE gender = '';
+UPDATE person SET nationality = NULL WHERE nationality = '';
@@ -1,21 +1,26 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 )
 
 const agifyURL = "https://api.agify.io"
 
-type AgeFetcher struct{}
+type AgeFetcher struct {
+	api *api
+}
 
-func NewAgeFetcher() *AgeFetcher {
-	return &AgeFetcher{}
+// NewAgeFetcher returns the fetcher of the public API configured by the options.
+func NewAgeFetcher(opts ...Option) *AgeFetcher {
+	return &AgeFetcher{api: newAPI(agifyURL, opts...)}
 }
 
 // Fetch returns the response from https://api.agify.io?name=name
-func (*AgeFetcher) Fetch(name string) ([]byte, error) {
-	data, err := get(agifyURL, name)
+//
+// If the age of the name is unknown ErrFindAge is returned.
+func (f *AgeFetcher) Fetch(ctx context.Context, name string) ([]byte, error) {
+	data, err := get(ctx, f.api, name)
 	if err != nil {
 		return nil, err
 	}
@@ -25,11 +30,33 @@ func (*AgeFetcher) Fetch(name string) ([]byte, error) {
 		return nil, err
 	}
 
-	return []byte(fmt.Sprintf(`{"age":%d}`, resp.Age)), nil
+	return ageFromResponse(resp)
+}
+
+// FetchBatch returns the responses from https://api.agify.io?name[]=name
+// requested by batches of MaxBatchSize names.
+func (f *AgeFetcher) FetchBatch(ctx context.Context, names []string) ([]Result, error) {
+	return fetchNames(ctx, f.api, names, ageFromResponse)
+}
+
+func ageFromResponse(resp agifyResponse) ([]byte, error) {
+	if resp.Age == nil {
+		return nil, ErrFindAge
+	}
+
+	return json.Marshal(&ageResult{
+		Age:   *resp.Age,
+		Count: resp.Count,
+	})
 }
 
 type agifyResponse struct {
 	Count int    `json:"count"`
 	Name  string `json:"name"`
-	Age   int    `json:"age"`
+	Age   *int   `json:"age"`
+}
+
+type ageResult struct {
+	Age   int `json:"age"`
+	Count int `json:"ageCount"`
 }
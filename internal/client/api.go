@@ -0,0 +1,57 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout is the default timeout of the requests to the provider API.
+const DefaultTimeout = 5 * time.Second
+
+// api holds the connection settings of the provider API.
+type api struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// Option configures the connection to the provider API.
+type Option func(a *api)
+
+// WithBaseURL sets the base URL of the API, the empty URL keeps the public one.
+func WithBaseURL(baseURL string) Option {
+	return func(a *api) {
+		if baseURL != "" {
+			a.baseURL = baseURL
+		}
+	}
+}
+
+// WithAPIKey sets the key sent as the apikey param of the requests.
+func WithAPIKey(key string) Option {
+	return func(a *api) {
+		a.apiKey = key
+	}
+}
+
+// WithHTTPClient sets the client of the requests, nil keeps the client with DefaultTimeout.
+func WithHTTPClient(c *http.Client) Option {
+	return func(a *api) {
+		if c != nil {
+			a.httpClient = c
+		}
+	}
+}
+
+func newAPI(baseURL string, opts ...Option) *api {
+	a := &api{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
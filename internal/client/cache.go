@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"strings"
+	"time"
+
+	"github.com/insan1a/exile/internal/storage/cache"
+)
+
+// negativeMarker prefixes the cached errors of the unknown names.
+const negativeMarker = '!'
+
+// cacheMetrics holds the hits and misses of the enrichment cache by provider.
+var cacheMetrics = expvar.NewMap("enrichment_cache")
+
+// notFoundErrs are the errors cached for the unknown names.
+var notFoundErrs = []error{ErrFindAge, ErrFindGender, ErrFindNationality}
+
+// CachedFetcher caches the responses of the fetcher by the normalized name.
+//
+// The unknown names are cached as well for negativeTTL,
+// so the provider is not queried for them again.
+type CachedFetcher struct {
+	provider    string
+	fetcher     Fetcher
+	cache       cache.Cache
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+// NewCachedFetcher returns the fetcher caching the responses of the provider.
+func NewCachedFetcher(provider string, fetcher Fetcher, c cache.Cache, ttl, negativeTTL time.Duration) *CachedFetcher {
+	return &CachedFetcher{
+		provider:    provider,
+		fetcher:     fetcher,
+		cache:       c,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}
+}
+
+// Fetch returns the cached response or fetches and caches it.
+//
+// The cache failures are ignored and the provider is queried.
+func (f *CachedFetcher) Fetch(ctx context.Context, name string) ([]byte, error) {
+	key := f.key(name)
+
+	if data, found, err := f.cache.Get(ctx, key); err == nil && found {
+		if len(data) > 0 && data[0] == negativeMarker {
+			cacheMetrics.Add(f.provider+".negative_hits", 1)
+			return nil, notFoundErr(string(data[1:]))
+		}
+
+		cacheMetrics.Add(f.provider+".hits", 1)
+		return data, nil
+	}
+
+	cacheMetrics.Add(f.provider+".misses", 1)
+
+	data, err := f.fetcher.Fetch(ctx, name)
+	f.store(ctx, name, Result{Data: data, Err: err})
+
+	return data, err
+}
+
+// FetchBatch returns the cached responses and fetches the missed names at once.
+func (f *CachedFetcher) FetchBatch(ctx context.Context, names []string) ([]Result, error) {
+	results := make([]Result, len(names))
+
+	missed := make([]int, 0, len(names))
+	for i, name := range names {
+		data, found, err := f.cache.Get(ctx, f.key(name))
+		if err != nil || !found {
+			missed = append(missed, i)
+			continue
+		}
+
+		if len(data) > 0 && data[0] == negativeMarker {
+			cacheMetrics.Add(f.provider+".negative_hits", 1)
+			results[i].Err = notFoundErr(string(data[1:]))
+			continue
+		}
+
+		cacheMetrics.Add(f.provider+".hits", 1)
+		results[i].Data = data
+	}
+
+	if len(missed) == 0 {
+		return results, nil
+	}
+
+	cacheMetrics.Add(f.provider+".misses", int64(len(missed)))
+
+	missedNames := make([]string, len(missed))
+	for i, idx := range missed {
+		missedNames[i] = names[idx]
+	}
+
+	fetched, err := fetchBatch(ctx, f.fetcher, missedNames)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, idx := range missed {
+		results[idx] = fetched[i]
+		f.store(ctx, names[idx], fetched[i])
+	}
+
+	return results, nil
+}
+
+// store caches the result of the name, the unknown names are cached for negativeTTL.
+func (f *CachedFetcher) store(ctx context.Context, name string, r Result) {
+	if r.Err == nil {
+		_ = f.cache.Set(ctx, f.key(name), r.Data, f.ttl)
+		return
+	}
+
+	if isNotFound(r.Err) && f.negativeTTL > 0 {
+		_ = f.cache.Set(ctx, f.key(name), append([]byte{negativeMarker}, r.Err.Error()...), f.negativeTTL)
+	}
+}
+
+// key returns the cache key of the name for the provider.
+func (f *CachedFetcher) key(name string) string {
+	return CacheKey(f.provider, name)
+}
+
+// CacheKey returns the key the response of the provider for the name is cached by.
+func CacheKey(provider, name string) string {
+	return "enrichment:" + provider + ":" + NormalizeName(name)
+}
+
+// NormalizeName returns the name in lower case without surrounding spaces.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func isNotFound(err error) bool {
+	for _, target := range notFoundErrs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notFoundErr returns the error of the unknown name with given message.
+func notFoundErr(msg string) error {
+	for _, target := range notFoundErrs {
+		if target.Error() == msg {
+			return target
+		}
+	}
+
+	return errors.New(msg)
+}
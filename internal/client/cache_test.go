@@ -0,0 +1,95 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/insan1a/exile/internal/client"
+	"github.com/insan1a/exile/internal/client/mocks"
+	"github.com/insan1a/exile/internal/storage/cache/memory"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCachedFetcher_Fetch(t *testing.T) {
+	c, err := memory.New(10)
+	if err != nil {
+		t.Fatalf("memory.New() error = %v", err)
+	}
+
+	fetcher := mocks.NewFetcher(t)
+	cached := client.NewCachedFetcher(client.ProviderAgify, fetcher, c, time.Minute, time.Minute)
+
+	fetcher.On("Fetch", mock.Anything, "Ivan").Once().Return([]byte(`{"age":40}`), nil)
+	fetcher.On("Fetch", mock.Anything, "Xyzzy").Once().Return(nil, client.ErrFindAge)
+
+	for _, name := range []string{"Ivan", " ivan", "IVAN"} {
+		data, err := cached.Fetch(context.Background(), name)
+		if err != nil {
+			t.Fatalf("CachedFetcher.Fetch(%q) error = %v", name, err)
+		}
+		if string(data) != `{"age":40}` {
+			t.Errorf("CachedFetcher.Fetch(%q) = %s", name, data)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err = cached.Fetch(context.Background(), "Xyzzy"); !errors.Is(err, client.ErrFindAge) {
+			t.Errorf("CachedFetcher.Fetch() error = %v, want %v", err, client.ErrFindAge)
+		}
+	}
+}
+
+func TestCachedFetcher_FetchBatch(t *testing.T) {
+	c, err := memory.New(10)
+	if err != nil {
+		t.Fatalf("memory.New() error = %v", err)
+	}
+
+	fetcher := mocks.NewBatchFetcher(t)
+	cached := client.NewCachedFetcher(client.ProviderAgify, fetcher, c, time.Minute, time.Minute)
+
+	fetcher.On("Fetch", mock.Anything, "Ivan").Once().Return([]byte(`{"age":40}`), nil)
+	fetcher.On("FetchBatch", mock.Anything, []string{"Olga", "Xyzzy"}).Once().Return([]client.Result{
+		{Data: []byte(`{"age":30}`)},
+		{Err: client.ErrFindAge},
+	}, nil)
+
+	if _, err = cached.Fetch(context.Background(), "Ivan"); err != nil {
+		t.Fatalf("CachedFetcher.Fetch() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		results, err := cached.FetchBatch(context.Background(), []string{"Ivan", "Olga", "Xyzzy"})
+		if err != nil {
+			t.Fatalf("CachedFetcher.FetchBatch() error = %v", err)
+		}
+
+		if string(results[0].Data) != `{"age":40}` || string(results[1].Data) != `{"age":30}` {
+			t.Errorf("CachedFetcher.FetchBatch() = %v", results)
+		}
+		if !errors.Is(results[2].Err, client.ErrFindAge) {
+			t.Errorf("CachedFetcher.FetchBatch() error = %v, want %v", results[2].Err, client.ErrFindAge)
+		}
+	}
+}
+
+func TestCachedFetcher_FetchError(t *testing.T) {
+	c, err := memory.New(10)
+	if err != nil {
+		t.Fatalf("memory.New() error = %v", err)
+	}
+
+	fetcher := mocks.NewFetcher(t)
+	cached := client.NewCachedFetcher(client.ProviderAgify, fetcher, c, time.Minute, time.Minute)
+
+	apiErr := client.APIError{Message: "internal error", StatusCode: 500}
+	fetcher.On("Fetch", mock.Anything, "Ivan").Twice().Return(nil, apiErr)
+
+	for i := 0; i < 2; i++ {
+		if _, err = cached.Fetch(context.Background(), "Ivan"); err == nil {
+			t.Errorf("CachedFetcher.Fetch() error = nil, want the api error")
+		}
+	}
+}
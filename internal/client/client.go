@@ -1,18 +1,42 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/insan1a/exile/internal/lib/apitools"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name Fetcher --output ./mocks --outpkg mocks
 type Fetcher interface {
-	Fetch(name string) ([]byte, error)
+	// Fetch returns the data of the name, the request is canceled with the context.
+	Fetch(ctx context.Context, name string) ([]byte, error)
+}
+
+// MaxBatchSize is the maximum count of names the providers accept in one request.
+const MaxBatchSize = 10
+
+// Result is the response of the provider for one name of the batch.
+type Result struct {
+	Data []byte
+	Err  error
+}
+
+// BatchFetcher fetches the data of several names at once.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name BatchFetcher --output ./mocks --outpkg mocks
+type BatchFetcher interface {
+	Fetcher
+	// FetchBatch returns the results of the names in the same order.
+	FetchBatch(ctx context.Context, names []string) ([]Result, error)
 }
 
 var (
@@ -20,8 +44,91 @@ var (
 	ErrFindNationality = errors.New("could not find nationality for the name")
 	ErrFindGender      = errors.New("could not find gender for the name")
 	ErrFindAge         = errors.New("could not find age for the name")
+	ErrBatchSize       = errors.New("the count of results does not match the count of names")
+	ErrRateLimited     = errors.New("the rate limit of the API is exceeded")
 )
 
+// defaultRetryAfter is the wait after the rate limit error without the reset header.
+const defaultRetryAfter = time.Minute
+
+// RateLimitError is returned when the quota of the API is exhausted.
+type RateLimitError struct {
+	API string
+	// RetryAfter is the time until the rate limit window resets.
+	RetryAfter time.Duration
+}
+
+// Error returns the message of the RateLimitError.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: %v, retry after %s", e.API, ErrRateLimited, e.RetryAfter)
+}
+
+// Is reports whether the target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RetryAfter returns the wait of the rate limit error.
+func RetryAfter(err error) (time.Duration, bool) {
+	var rlErr *RateLimitError
+	if !errors.As(err, &rlErr) {
+		return 0, false
+	}
+
+	return rlErr.RetryAfter, true
+}
+
+// TimeoutError is returned when the request is canceled by the context.
+type TimeoutError struct {
+	API string
+	Err error
+}
+
+// Error returns the message of the TimeoutError.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: the request is canceled: %v", e.API, e.Err)
+}
+
+// Unwrap returns the error of the context.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether the request failed by the error worth retrying:
+// the timeout, the rate limit or the server error of the API.
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var apiErr APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusInternalServerError
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*apitools.Limiter)
+)
+
+// limiterOf returns the limiter tracking the rate limit of the API.
+func limiterOf(apiURL string) *apitools.Limiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	l, ok := limiters[apiURL]
+	if !ok {
+		l = apitools.NewLimiter()
+		limiters[apiURL] = l
+	}
+
+	return l
+}
+
 // APIError represents an error returned from the Genderize API.
 type APIError struct {
 	// Error from API request
@@ -37,32 +144,142 @@ func (e APIError) Error() string {
 	return e.Message
 }
 
-func get(apiURL string, name string) ([]byte, error) {
+func get(ctx context.Context, a *api, name string) ([]byte, error) {
 	if name == "" {
 		return nil, ErrNameEmpty
 	}
 
-	params := url.Values{
+	return query(ctx, a, url.Values{
 		"name": []string{name},
+	}, 1)
+}
+
+// getBatch returns the responses of the API for the names
+// requested by chunks of MaxBatchSize names using the name[] param.
+func getBatch[T any](ctx context.Context, a *api, names []string) ([]T, error) {
+	resps := make([]T, 0, len(names))
+
+	for start := 0; start < len(names); start += MaxBatchSize {
+		chunk := names[start:min(start+MaxBatchSize, len(names))]
+
+		data, err := query(ctx, a, url.Values{
+			"name[]": chunk,
+		}, len(chunk))
+		if err != nil {
+			return nil, err
+		}
+
+		var chunkResps []T
+		if err = json.Unmarshal(data, &chunkResps); err != nil {
+			return nil, err
+		}
+
+		if len(chunkResps) != len(chunk) {
+			return nil, ErrBatchSize
+		}
+
+		resps = append(resps, chunkResps...)
+	}
+
+	return resps, nil
+}
+
+// fetchNames fetches the responses of the names by batches
+// and converts each of them into the result.
+//
+// The empty names are not requested and get ErrNameEmpty.
+func fetchNames[T any](ctx context.Context, a *api, names []string, convert func(T) ([]byte, error)) ([]Result, error) {
+	results := make([]Result, len(names))
+	indexes := make([]int, 0, len(names))
+	requested := make([]string, 0, len(names))
+
+	for i, name := range names {
+		if name == "" {
+			results[i].Err = ErrNameEmpty
+			continue
+		}
+
+		indexes = append(indexes, i)
+		requested = append(requested, name)
+	}
+
+	if len(requested) == 0 {
+		return results, nil
+	}
+
+	resps, err := getBatch[T](ctx, a, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, resp := range resps {
+		results[indexes[i]].Data, results[indexes[i]].Err = convert(resp)
+	}
+
+	return results, nil
+}
+
+// fetchBatch fetches the names at once if the fetcher supports batches,
+// otherwise one by one.
+func fetchBatch(ctx context.Context, f Fetcher, names []string) ([]Result, error) {
+	if bf, ok := f.(BatchFetcher); ok {
+		return bf.FetchBatch(ctx, names)
 	}
 
-	endpoint, err := url.Parse(apiURL)
+	results := make([]Result, len(names))
+	for i, name := range names {
+		results[i].Data, results[i].Err = f.Fetch(ctx, name)
+	}
+
+	return results, nil
+}
+
+// query requests the API with the params holding the given count of names.
+//
+// The rate limit of the API is tracked from every response,
+// if the quota is exhausted the API is not requested and RateLimitError is returned.
+func query(ctx context.Context, a *api, params url.Values, names int) ([]byte, error) {
+	endpoint, err := url.Parse(a.baseURL)
 	if err != nil {
 		return nil, err
 	}
+	if a.apiKey != "" {
+		params.Set("apikey", a.apiKey)
+	}
 	endpoint.RawQuery = params.Encode()
 
-	req := &http.Request{
-		Method: http.MethodGet,
-		URL:    endpoint,
+	limiter := limiterOf(a.baseURL)
+	if wait, ok := limiter.Reserve(int64(names)); !ok {
+		return nil, &RateLimitError{API: a.baseURL, RetryAfter: wait}
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
 	if err != nil {
 		return nil, err
 	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, &TimeoutError{API: a.baseURL, Err: ctx.Err()}
+		}
+
+		return nil, err
+	}
 	defer resp.Body.Close()
 
+	rt := apitools.RateLimitFromHeaders(resp)
+	limiter.Update(rt)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := defaultRetryAfter
+		if rt != nil {
+			retryAfter = time.Duration(rt.Reset) * time.Second
+		}
+
+		return nil, &RateLimitError{API: a.baseURL, RetryAfter: retryAfter}
+	}
+
 	success := resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices
 	decoder := json.NewDecoder(resp.Body)
 
@@ -71,14 +288,15 @@ func get(apiURL string, name string) ([]byte, error) {
 			StatusCode: resp.StatusCode,
 		}
 
-		if err = decoder.Decode(&apiErr); err != nil {
+		if err = decoder.Decode(&apiErr); err != nil && apiErr.StatusCode < http.StatusInternalServerError {
 			return nil, err
 		}
-
-		if rt := apitools.RateLimitFromHeaders(resp); rt != nil {
-			apiErr.RateLimit = rt
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(apiErr.StatusCode)
 		}
 
+		apiErr.RateLimit = rt
+
 		return nil, apiErr
 	}
 
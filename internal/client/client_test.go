@@ -1,11 +1,38 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-faker/faker/v4"
 )
 
+// newStubAPI returns the server answering the requests of the names with their responses
+// and with the 422 error to the requests of the other names.
+func newStubAPI(t *testing.T, resps map[string]string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := resps[r.URL.Query().Get("name")]
+		if !ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error":"Invalid 'name' parameter"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
 func TestAPIError_Error(t *testing.T) {
 	tests := []struct {
 		name string
@@ -28,6 +55,8 @@ func TestAPIError_Error(t *testing.T) {
 }
 
 func Test_get(t *testing.T) {
+	srv := newStubAPI(t, map[string]string{"Ivan": `{"count":1000,"name":"Ivan","age":40}`})
+
 	type args struct {
 		apiURL string
 		name   string
@@ -52,31 +81,15 @@ func Test_get(t *testing.T) {
 		{
 			name: "not success http request",
 			args: args{
-				apiURL: "https://jsonplaceholder.typicode.com/ghghgh",
-				name:   "Ivan",
+				apiURL: srv.URL,
+				name:   "Olga",
 			},
 			wantErr: true,
 		},
 		{
-			name: "nationalize api request",
+			name: "api request",
 			args: args{
-				apiURL: nationalizeURL,
-				name:   "Ivan",
-			},
-			wantErr: false,
-		},
-		{
-			name: "genderize api request",
-			args: args{
-				apiURL: genderizeURL,
-				name:   "Ivan",
-			},
-			wantErr: false,
-		},
-		{
-			name: "agify api request",
-			args: args{
-				apiURL: agifyURL,
+				apiURL: srv.URL,
 				name:   "Ivan",
 			},
 			wantErr: false,
@@ -86,7 +99,7 @@ func Test_get(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			_, err := get(tt.args.apiURL, tt.args.name)
+			_, err := get(context.Background(), newAPI(tt.args.apiURL), tt.args.name)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -94,3 +107,177 @@ func Test_get(t *testing.T) {
 		})
 	}
 }
+
+func Test_fetchNames(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		names := r.URL.Query()["name[]"]
+		resps := make([]agifyResponse, len(names))
+		for i, name := range names {
+			resps[i].Name = name
+			if name != "Xyzzy" {
+				age := len(name)
+				resps[i].Age = &age
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(resps)
+	}))
+	defer srv.Close()
+
+	names := []string{"Xyzzy", ""}
+	for i := 0; i < MaxBatchSize; i++ {
+		names = append(names, "Ivan")
+	}
+
+	results, err := fetchNames(context.Background(), newAPI(srv.URL), names, ageFromResponse)
+	if err != nil {
+		t.Fatalf("fetchNames() error = %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("fetchNames() sent %d requests, want 2", requests)
+	}
+	if !errors.Is(results[0].Err, ErrFindAge) {
+		t.Errorf("fetchNames() error = %v, want %v", results[0].Err, ErrFindAge)
+	}
+	if !errors.Is(results[1].Err, ErrNameEmpty) {
+		t.Errorf("fetchNames() error = %v, want %v", results[1].Err, ErrNameEmpty)
+	}
+	for _, r := range results[2:] {
+		if string(r.Data) != `{"age":4,"ageCount":0}` {
+			t.Errorf("fetchNames() data = %s", r.Data)
+		}
+	}
+}
+
+func Test_queryRateLimit(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		w.Header().Set("X-Rate-Limit-Limit", "1000")
+		w.Header().Set("X-Rate-Limit-Remaining", "1")
+		w.Header().Set("X-Rate-Limit-Reset", "60")
+		if requests > 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"Request limit reached"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"age":40}`))
+	}))
+	defer srv.Close()
+
+	if _, err := get(context.Background(), newAPI(srv.URL), "Ivan"); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+
+	if _, err := fetchNames(context.Background(), newAPI(srv.URL), []string{"Ivan", "Olga"}, ageFromResponse); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("fetchNames() error = %v, want %v", err, ErrRateLimited)
+	}
+	if requests != 1 {
+		t.Errorf("the exhausted quota was requested")
+	}
+
+	_, err := get(context.Background(), newAPI(srv.URL), "Ivan")
+	if wait, ok := RetryAfter(err); !ok || wait != time.Minute {
+		t.Errorf("get() error = %v, want the rate limit error", err)
+	}
+	if requests != 2 {
+		t.Errorf("the remaining quota was not requested")
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "rate limit",
+			err:  fmt.Errorf("agify: %w", &RateLimitError{RetryAfter: time.Second}),
+			want: true,
+		},
+		{
+			name: "server error",
+			err:  APIError{StatusCode: http.StatusBadGateway},
+			want: true,
+		},
+		{
+			name: "client error",
+			err:  APIError{StatusCode: http.StatusUnprocessableEntity},
+			want: false,
+		},
+		{
+			name: "timeout",
+			err:  context.DeadlineExceeded,
+			want: true,
+		},
+		{
+			name: "unknown name",
+			err:  ErrFindAge,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_queryServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`<html>bad gateway</html>`))
+	}))
+	defer srv.Close()
+
+	if _, err := get(context.Background(), newAPI(srv.URL), "Ivan"); !IsTransient(err) {
+		t.Errorf("get() error = %v, want the transient error", err)
+	}
+}
+
+func Test_queryAPIKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apikey") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"Invalid API key"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"age":40}`))
+	}))
+	defer srv.Close()
+
+	if _, err := get(context.Background(), newAPI(srv.URL, WithAPIKey("secret")), "Ivan"); err != nil {
+		t.Errorf("get() error = %v", err)
+	}
+
+	if _, err := get(context.Background(), newAPI(srv.URL), "Ivan"); err == nil {
+		t.Errorf("get() error = nil without the api key")
+	}
+}
+
+func Test_queryTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := get(ctx, newAPI(srv.URL), "Ivan")
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || !IsTransient(err) {
+		t.Errorf("get() error = %v, want the timeout error", err)
+	}
+}
@@ -0,0 +1,312 @@
+package client
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/insan1a/exile/internal/models"
+)
+
+var (
+	ErrDatasetFormat = errors.New("the dataset format is unsupported")
+	ErrDatasetMode   = errors.New("the dataset mode is unknown")
+)
+
+// The modes of the dataset.
+const (
+	// DatasetModePrimary makes the providers look the names up in the dataset only.
+	DatasetModePrimary = "primary"
+	// DatasetModeFallback makes the providers look the names up in the dataset if the APIs fail.
+	DatasetModeFallback = "fallback"
+)
+
+// DatasetEntry is the age, gender and country of the name in the dataset,
+// the zero values are unknown.
+type DatasetEntry struct {
+	Name               string  `json:"name"`
+	Age                int     `json:"age"`
+	Gender             string  `json:"gender"`
+	GenderProbability  float64 `json:"gender_probability"`
+	Country            string  `json:"country"`
+	CountryProbability float64 `json:"country_probability"`
+	// Count is the count of samples the entry is based on.
+	Count int `json:"count"`
+}
+
+// Dataset is the local dataset of the names loaded from the CSV or JSON file.
+//
+// The CSV file has the header with the names of the columns: name, age, gender,
+// gender_probability, country, country_probability and count, only the name is required.
+// The JSON file holds the array of the entries with the same keys.
+type Dataset struct {
+	path string
+
+	mu      sync.RWMutex
+	entries map[string]DatasetEntry
+	modTime time.Time
+}
+
+// LoadDataset loads the dataset from the file, the format is detected by its extension.
+func LoadDataset(path string) (*Dataset, error) {
+	d := &Dataset{path: path}
+	if _, err := d.Reload(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Reload loads the dataset again if the file is modified since the last load
+// and reports whether it is reloaded.
+//
+// If the file is invalid the loaded entries are kept and the error is returned.
+func (d *Dataset) Reload() (bool, error) {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return false, fmt.Errorf("Dataset.Reload: %w", err)
+	}
+
+	d.mu.RLock()
+	modTime := d.modTime
+	d.mu.RUnlock()
+
+	if info.ModTime().Equal(modTime) {
+		return false, nil
+	}
+
+	f, err := os.Open(d.path)
+	if err != nil {
+		return false, fmt.Errorf("Dataset.Reload: %w", err)
+	}
+	defer f.Close()
+
+	var entries []DatasetEntry
+	switch strings.ToLower(filepath.Ext(d.path)) {
+	case ".csv":
+		entries, err = readCSV(f)
+	case ".json":
+		err = json.NewDecoder(f).Decode(&entries)
+	default:
+		err = ErrDatasetFormat
+	}
+	if err != nil {
+		return false, fmt.Errorf("Dataset.Reload: %w", err)
+	}
+
+	byName := make(map[string]DatasetEntry, len(entries))
+	for _, e := range entries {
+		byName[NormalizeName(e.Name)] = e
+	}
+
+	d.mu.Lock()
+	d.entries = byName
+	d.modTime = info.ModTime()
+	d.mu.Unlock()
+
+	return true, nil
+}
+
+// Watch reloads the modified dataset every interval until the context is done,
+// the reload failures are passed to onError.
+func (d *Dataset) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Option returns the option of the providers using the dataset in given mode.
+//
+// If the mode is unknown ErrDatasetMode is returned.
+func (d *Dataset) Option(mode string) (ProviderOption, error) {
+	switch mode {
+	case DatasetModePrimary:
+		return WithDataset(d), nil
+	case DatasetModeFallback:
+		return WithDatasetFallback(d), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrDatasetMode, mode)
+}
+
+// Lookup returns the entry of the name.
+func (d *Dataset) Lookup(name string) (DatasetEntry, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	e, ok := d.entries[NormalizeName(name)]
+	return e, ok
+}
+
+// Fetcher returns the fetcher answering as the provider with given name from the dataset.
+//
+// If the provider is unknown ErrUnknownProvider is returned.
+func (d *Dataset) Fetcher(provider string) (*DatasetFetcher, error) {
+	switch provider {
+	case ProviderAgify, ProviderGenderize, ProviderNationalize:
+		return &DatasetFetcher{dataset: d, provider: provider}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+}
+
+// DatasetFetcher looks the names up in the dataset
+// and returns the same data as the fetcher of the provider.
+type DatasetFetcher struct {
+	dataset  *Dataset
+	provider string
+}
+
+// Fetch returns the data of the name from the dataset with the dataset source.
+//
+// If the name or its field is not in the dataset
+// the error of the unknown name of the provider is returned.
+func (f *DatasetFetcher) Fetch(ctx context.Context, name string) ([]byte, error) {
+	if name == "" {
+		return nil, ErrNameEmpty
+	}
+
+	data, err := f.fetch(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// the source is added to tell the fields of the dataset from the ones of the API
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	fields["source"] = json.RawMessage(`"` + models.SourceDataset + `"`)
+
+	return json.Marshal(fields)
+}
+
+// fetch returns the data of the name in the shape of the data of the provider.
+func (f *DatasetFetcher) fetch(name string) ([]byte, error) {
+	e, _ := f.dataset.Lookup(name)
+
+	switch f.provider {
+	case ProviderAgify:
+		if e.Age == 0 {
+			return nil, ErrFindAge
+		}
+
+		return ageFromResponse(agifyResponse{Count: e.Count, Name: e.Name, Age: &e.Age})
+	case ProviderGenderize:
+		return genderFromResponse(genderizeResponse{
+			Count:       e.Count,
+			Name:        e.Name,
+			Gender:      e.Gender,
+			Probability: e.GenderProbability,
+		})
+	default:
+		if e.Country == "" {
+			return nil, ErrFindNationality
+		}
+
+		return json.Marshal(&nationalityResult{
+			Nationality:   e.Country,
+			Probability:   e.CountryProbability,
+			Count:         e.Count,
+			Nationalities: []models.Nationality{{CountryID: e.Country, Probability: e.CountryProbability}},
+		})
+	}
+}
+
+// readCSV reads the entries from the CSV with the header.
+func readCSV(r io.Reader) ([]DatasetEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("%w: the name column is missing", ErrDatasetFormat)
+	}
+
+	var entries []DatasetEntry
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		e := DatasetEntry{
+			Name:    value("name"),
+			Gender:  value("gender"),
+			Country: strings.ToUpper(value("country")),
+		}
+
+		if e.Age, err = atoi(value("age")); err != nil {
+			return nil, fmt.Errorf("line %d: age: %w", line, err)
+		}
+		if e.Count, err = atoi(value("count")); err != nil {
+			return nil, fmt.Errorf("line %d: count: %w", line, err)
+		}
+		if e.GenderProbability, err = atof(value("gender_probability")); err != nil {
+			return nil, fmt.Errorf("line %d: gender_probability: %w", line, err)
+		}
+		if e.CountryProbability, err = atof(value("country_probability")); err != nil {
+			return nil, fmt.Errorf("line %d: country_probability: %w", line, err)
+		}
+
+		entries = append(entries, e)
+	}
+}
+
+// atoi parses the integer, the empty string is zero.
+func atoi(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(s)
+}
+
+// atof parses the float, the empty string is zero.
+func atof(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,279 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/insan1a/exile/internal/models"
+)
+
+// writeDataset writes the dataset file with given name to the temporary directory.
+func writeDataset(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	return path
+}
+
+func TestLoadDataset(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    DatasetEntry
+		wantErr bool
+	}{
+		{
+			name:    "csv",
+			file:    "names.csv",
+			content: "name,age,gender,gender_probability,country\nIvan,40,male,0.99,ru\n",
+			want:    DatasetEntry{Name: "Ivan", Age: 40, Gender: "male", GenderProbability: 0.99, Country: "RU"},
+		},
+		{
+			name:    "json",
+			file:    "names.json",
+			content: `[{"name":"Ivan","age":40,"country":"RU","country_probability":0.5}]`,
+			want:    DatasetEntry{Name: "Ivan", Age: 40, Country: "RU", CountryProbability: 0.5},
+		},
+		{
+			name:    "csv without name",
+			file:    "names.csv",
+			content: "age\n40\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid age",
+			file:    "names.csv",
+			content: "name,age\nIvan,old\n",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			file:    "names.txt",
+			content: "Ivan",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := LoadDataset(writeDataset(t, tt.file, tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadDataset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, ok := d.Lookup(" ivan")
+			if !ok || got != tt.want {
+				t.Errorf("Dataset.Lookup() = %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataset_Reload(t *testing.T) {
+	path := writeDataset(t, "names.csv", "name,age\nIvan,40\n")
+
+	d, err := LoadDataset(path)
+	if err != nil {
+		t.Fatalf("LoadDataset() error = %v", err)
+	}
+
+	if reloaded, err := d.Reload(); err != nil || reloaded {
+		t.Errorf("Dataset.Reload() = %v, %v for the unmodified file", reloaded, err)
+	}
+
+	modified := time.Now().Add(time.Minute)
+	if err = os.WriteFile(path, []byte("name,age\nIvan,41\n"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err = os.Chtimes(path, modified, modified); err != nil {
+		t.Fatalf("os.Chtimes() error = %v", err)
+	}
+
+	if reloaded, err := d.Reload(); err != nil || !reloaded {
+		t.Fatalf("Dataset.Reload() = %v, %v for the modified file", reloaded, err)
+	}
+	if e, _ := d.Lookup("Ivan"); e.Age != 41 {
+		t.Errorf("Dataset.Lookup() age = %d, want 41", e.Age)
+	}
+
+	modified = modified.Add(time.Minute)
+	if err = os.WriteFile(path, []byte("age\n42\n"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	if err = os.Chtimes(path, modified, modified); err != nil {
+		t.Fatalf("os.Chtimes() error = %v", err)
+	}
+
+	if _, err = d.Reload(); !errors.Is(err, ErrDatasetFormat) {
+		t.Errorf("Dataset.Reload() error = %v, want %v", err, ErrDatasetFormat)
+	}
+	if e, _ := d.Lookup("Ivan"); e.Age != 41 {
+		t.Errorf("the invalid dataset replaced the loaded one")
+	}
+}
+
+func TestDatasetFetcher_Fetch(t *testing.T) {
+	d, err := LoadDataset(writeDataset(t, "names.csv",
+		"name,age,gender,gender_probability,country,country_probability,count\nIvan,40,male,0.99,RU,0.5,100\nOlga,,,,,,\n"))
+	if err != nil {
+		t.Fatalf("LoadDataset() error = %v", err)
+	}
+
+	tests := []struct {
+		provider string
+		name     string
+		want     models.Person
+		wantErr  error
+	}{
+		{
+			provider: ProviderAgify,
+			name:     "Ivan",
+			want:     models.Person{Age: 40, AgeCount: 100},
+		},
+		{
+			provider: ProviderGenderize,
+			name:     "Ivan",
+			want:     models.Person{Gender: "male", GenderProbability: 0.99, GenderCount: 100},
+		},
+		{
+			provider: ProviderNationalize,
+			name:     "Ivan",
+			want: models.Person{
+				Nationality:            "RU",
+				NationalityProbability: 0.5,
+				NationalityCount:       100,
+				Nationalities:          []models.Nationality{{CountryID: "RU", Probability: 0.5}},
+			},
+		},
+		{
+			provider: ProviderAgify,
+			name:     "Olga",
+			wantErr:  ErrFindAge,
+		},
+		{
+			provider: ProviderGenderize,
+			name:     "Xyzzy",
+			wantErr:  ErrFindGender,
+		},
+		{
+			provider: ProviderNationalize,
+			name:     "",
+			wantErr:  ErrNameEmpty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider+" "+tt.name, func(t *testing.T) {
+			f, err := d.Fetcher(tt.provider)
+			if err != nil {
+				t.Fatalf("Dataset.Fetcher() error = %v", err)
+			}
+
+			p := &models.Person{Name: tt.name}
+			err = NewFetcherEnricher(tt.provider, f).Enrich(context.Background(), p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DatasetFetcher.Fetch() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if p.Age != tt.want.Age || p.AgeCount != tt.want.AgeCount ||
+				p.Gender != tt.want.Gender || p.GenderProbability != tt.want.GenderProbability ||
+				p.Nationality != tt.want.Nationality || len(p.Nationalities) != len(tt.want.Nationalities) {
+				t.Errorf("DatasetFetcher.Fetch() = %+v, want %+v", *p, tt.want)
+			}
+		})
+	}
+
+	if _, err = d.Fetcher("unknown"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("Dataset.Fetcher() error = %v, want %v", err, ErrUnknownProvider)
+	}
+}
+
+func TestFallbackFetcher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") == "Olga" {
+			_, _ = w.Write([]byte(`{"age":30}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	d, err := LoadDataset(writeDataset(t, "names.csv", "name,age\nIvan,40\nOlga,31\n"))
+	if err != nil {
+		t.Fatalf("LoadDataset() error = %v", err)
+	}
+
+	dataset, _ := d.Fetcher(ProviderAgify)
+	f := NewFallbackFetcher(NewAgeFetcher(WithBaseURL(srv.URL)), dataset)
+
+	tests := []struct {
+		name      string
+		want      string
+		transient bool
+	}{
+		{name: "Olga", want: `{"age":30,"ageCount":0}`},
+		{name: "Ivan", want: `{"age":40,"ageCount":0,"source":"dataset"}`},
+		{name: "Xyzzy", transient: true},
+	}
+	for _, tt := range tests {
+		data, err := f.Fetch(context.Background(), tt.name)
+		if tt.transient != IsTransient(err) {
+			t.Errorf("FallbackFetcher.Fetch(%q) error = %v", tt.name, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("FallbackFetcher.Fetch(%q) = %s, want %s", tt.name, data, tt.want)
+		}
+	}
+}
+
+func TestProviders_WithDataset(t *testing.T) {
+	d, err := LoadDataset(writeDataset(t, "names.json",
+		`[{"name":"Ivan","age":40,"gender":"male","gender_probability":0.99,"country":"RU","country_probability":0.5}]`))
+	if err != nil {
+		t.Fatalf("LoadDataset() error = %v", err)
+	}
+
+	opt, err := d.Option(DatasetModePrimary)
+	if err != nil {
+		t.Fatalf("Dataset.Option() error = %v", err)
+	}
+
+	enrichers, err := Select(Providers(WithAPI(ProviderAgify, WithBaseURL("http://127.0.0.1:0")), opt),
+		[]string{ProviderAgify, ProviderGenderize, ProviderNationalize})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+
+	registry, err := NewRegistry(enrichers...)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	p := &models.Person{Name: "Ivan"}
+	if err = registry.Enrich(context.Background(), p); err != nil {
+		t.Fatalf("Registry.Enrich() error = %v", err)
+	}
+	if p.Age != 40 || p.Gender != "male" || p.Nationality != "RU" {
+		t.Errorf("Registry.Enrich() = %+v", *p)
+	}
+
+	if _, err = d.Option("unknown"); !errors.Is(err, ErrDatasetMode) {
+		t.Errorf("Dataset.Option() error = %v, want %v", err, ErrDatasetMode)
+	}
+}
@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/storage/cache"
+)
+
+// Field is a person field filled by an enricher.
+type Field string
+
+const (
+	FieldAge         Field = "age"
+	FieldGender      Field = "gender"
+	FieldNationality Field = "nationality"
+)
+
+// The names of the providers.
+const (
+	ProviderAgify       = "agify"
+	ProviderGenderize   = "genderize"
+	ProviderNationalize = "nationalize"
+)
+
+var ErrUnknownProvider = errors.New("the provider is unknown")
+
+// Enricher fills the person fields using a provider.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name Enricher --output ./mocks --outpkg mocks
+type Enricher interface {
+	// Name returns the name of the provider.
+	Name() string
+	// Fields returns the person fields filled by the provider.
+	Fields() []Field
+	// Enrich fills the fields of the person.
+	Enrich(ctx context.Context, p *models.Person) error
+}
+
+// BatchEnricher fills the fields of several persons at once.
+type BatchEnricher interface {
+	Enricher
+	// EnrichBatch fills the fields of the people and returns the error of each person.
+	EnrichBatch(ctx context.Context, people []*models.Person) []error
+}
+
+// EnrichBatch enriches the people at once if the enricher supports batches,
+// otherwise one by one.
+func EnrichBatch(ctx context.Context, e Enricher, people []*models.Person) []error {
+	if be, ok := e.(BatchEnricher); ok {
+		return be.EnrichBatch(ctx, people)
+	}
+
+	errs := make([]error, len(people))
+	for i, p := range people {
+		errs[i] = e.Enrich(ctx, p)
+	}
+
+	return errs
+}
+
+// FetcherEnricher is an enricher filling the person
+// with the JSON response of the fetcher.
+type FetcherEnricher struct {
+	name    string
+	fetcher Fetcher
+	fields  []Field
+}
+
+// NewFetcherEnricher returns the enricher of the provider with given name
+// filling the given fields from the response of the fetcher.
+func NewFetcherEnricher(name string, fetcher Fetcher, fields ...Field) *FetcherEnricher {
+	return &FetcherEnricher{
+		name:    name,
+		fetcher: fetcher,
+		fields:  fields,
+	}
+}
+
+// Name returns the name of the provider.
+func (e *FetcherEnricher) Name() string {
+	return e.name
+}
+
+// Fields returns the person fields filled by the provider.
+func (e *FetcherEnricher) Fields() []Field {
+	return e.fields
+}
+
+// Enrich fetches the data by the person name and fills the person.
+func (e *FetcherEnricher) Enrich(ctx context.Context, p *models.Person) error {
+	data, err := e.fetcher.Fetch(ctx, p.Name)
+	if err != nil {
+		return err
+	}
+
+	return e.fill(p, data)
+}
+
+// EnrichBatch fetches the data of all the people at once and fills them.
+//
+// If the batch request fails its error is returned for every person.
+func (e *FetcherEnricher) EnrichBatch(ctx context.Context, people []*models.Person) []error {
+	errs := make([]error, len(people))
+
+	names := make([]string, len(people))
+	for i, p := range people {
+		names[i] = p.Name
+	}
+
+	results, err := fetchBatch(ctx, e.fetcher, names)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	for i, r := range results {
+		if r.Err != nil {
+			errs[i] = r.Err
+			continue
+		}
+
+		errs[i] = e.fill(people[i], r.Data)
+	}
+
+	return errs
+}
+
+// fill fills the person with the data and records the source of the filled fields,
+// the source is the provider unless the data holds another one.
+func (e *FetcherEnricher) fill(p *models.Person, data []byte) error {
+	if err := json.Unmarshal(data, p); err != nil {
+		return err
+	}
+
+	var origin struct {
+		Source string `json:"source"`
+	}
+	_ = json.Unmarshal(data, &origin)
+
+	source := models.FieldSource{Source: e.name, UpdatedAt: time.Now()}
+	if origin.Source != "" {
+		source.Source = origin.Source
+	}
+
+	provenance := make(models.Provenance, len(p.Provenance)+len(e.fields))
+	for f, s := range p.Provenance {
+		provenance[f] = s
+	}
+	for _, f := range e.fields {
+		if Filled(p, f) {
+			provenance[string(f)] = source
+		}
+	}
+	p.Provenance = provenance
+
+	return nil
+}
+
+// providersConfig holds the settings of the providers.
+type providersConfig struct {
+	apis       map[string][]Option
+	decorators []func(provider string, f Fetcher) Fetcher
+
+	dataset         *Dataset
+	datasetFallback bool
+}
+
+// ProviderOption configures the providers.
+type ProviderOption func(c *providersConfig)
+
+// WithAPI configures the connection to the API of the provider with given name.
+func WithAPI(provider string, opts ...Option) ProviderOption {
+	return func(c *providersConfig) {
+		c.apis[provider] = append(c.apis[provider], opts...)
+	}
+}
+
+// WithCache caches the responses of the providers.
+func WithCache(cache cache.Cache, ttl, negativeTTL time.Duration) ProviderOption {
+	return func(c *providersConfig) {
+		c.decorators = append(c.decorators, func(provider string, f Fetcher) Fetcher {
+			return NewCachedFetcher(provider, f, cache, ttl, negativeTTL)
+		})
+	}
+}
+
+// WithDataset makes the providers look the names up in the local dataset
+// instead of querying the APIs, the responses are not cached.
+func WithDataset(d *Dataset) ProviderOption {
+	return func(c *providersConfig) {
+		c.dataset = d
+		c.datasetFallback = false
+	}
+}
+
+// WithDatasetFallback makes the providers look the names up in the local dataset
+// if the APIs fail, only the responses of the APIs are cached.
+func WithDatasetFallback(d *Dataset) ProviderOption {
+	return func(c *providersConfig) {
+		c.dataset = d
+		c.datasetFallback = true
+	}
+}
+
+// Providers returns the enrichers of the public APIs by their names.
+func Providers(opts ...ProviderOption) map[string]Enricher {
+	cfg := &providersConfig{apis: make(map[string][]Option)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	fetchers := map[string]Fetcher{
+		ProviderAgify:       NewAgeFetcher(cfg.apis[ProviderAgify]...),
+		ProviderGenderize:   NewGenderFetcher(cfg.apis[ProviderGenderize]...),
+		ProviderNationalize: NewNationalityFetcher(cfg.apis[ProviderNationalize]...),
+	}
+
+	for name, f := range fetchers {
+		if cfg.dataset != nil && !cfg.datasetFallback {
+			fetchers[name], _ = cfg.dataset.Fetcher(name)
+			continue
+		}
+
+		for _, decorate := range cfg.decorators {
+			f = decorate(name, f)
+		}
+
+		if cfg.dataset != nil {
+			fallback, _ := cfg.dataset.Fetcher(name)
+			f = NewFallbackFetcher(f, fallback)
+		}
+		fetchers[name] = f
+	}
+
+	return map[string]Enricher{
+		ProviderAgify:       NewFetcherEnricher(ProviderAgify, fetchers[ProviderAgify], FieldAge),
+		ProviderGenderize:   NewFetcherEnricher(ProviderGenderize, fetchers[ProviderGenderize], FieldGender),
+		ProviderNationalize: NewFetcherEnricher(ProviderNationalize, fetchers[ProviderNationalize], FieldNationality),
+	}
+}
+
+// Select returns the providers with given names in given order.
+//
+// If the provider is not found ErrUnknownProvider is returned.
+func Select(providers map[string]Enricher, names []string) ([]Enricher, error) {
+	enrichers := make([]Enricher, 0, len(names))
+	for _, name := range names {
+		e, ok := providers[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+		}
+
+		enrichers = append(enrichers, e)
+	}
+
+	return enrichers, nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import "context"
+
+// FallbackFetcher queries the fallback fetcher for the names the primary one failed for.
+//
+// If both fail the error of the primary fetcher is returned,
+// so the rate limits and the transient failures of the primary are still reported.
+type FallbackFetcher struct {
+	primary  Fetcher
+	fallback Fetcher
+}
+
+// NewFallbackFetcher returns the fetcher falling back to the fallback one on the failures of the primary.
+func NewFallbackFetcher(primary, fallback Fetcher) *FallbackFetcher {
+	return &FallbackFetcher{primary: primary, fallback: fallback}
+}
+
+// Fetch returns the response of the primary fetcher or of the fallback one if the primary fails.
+func (f *FallbackFetcher) Fetch(ctx context.Context, name string) ([]byte, error) {
+	data, err := f.primary.Fetch(ctx, name)
+	if err == nil {
+		return data, nil
+	}
+
+	if fallbackData, fallbackErr := f.fallback.Fetch(ctx, name); fallbackErr == nil {
+		return fallbackData, nil
+	}
+
+	return nil, err
+}
+
+// FetchBatch fetches the names by the primary fetcher at once
+// and the failed ones by the fallback fetcher.
+func (f *FallbackFetcher) FetchBatch(ctx context.Context, names []string) ([]Result, error) {
+	results, err := fetchBatch(ctx, f.primary, names)
+	if err != nil {
+		results = make([]Result, len(names))
+		for i := range results {
+			results[i].Err = err
+		}
+	}
+
+	for i, r := range results {
+		if r.Err == nil {
+			continue
+		}
+
+		if data, fallbackErr := f.fallback.Fetch(ctx, names[i]); fallbackErr == nil {
+			results[i] = Result{Data: data}
+		}
+	}
+
+	return results, nil
+}
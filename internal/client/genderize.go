@@ -1,21 +1,26 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 )
 
 const genderizeURL = "https://api.genderize.io"
 
-type GenderFetcher struct{}
+type GenderFetcher struct {
+	api *api
+}
 
-func NewGenderFetcher() *GenderFetcher {
-	return &GenderFetcher{}
+// NewGenderFetcher returns the fetcher of the public API configured by the options.
+func NewGenderFetcher(opts ...Option) *GenderFetcher {
+	return &GenderFetcher{api: newAPI(genderizeURL, opts...)}
 }
 
 // Fetch returns the response from https://api.genderize.io?name=name
-func (*GenderFetcher) Fetch(name string) ([]byte, error) {
-	data, err := get(genderizeURL, name)
+//
+// If the gender of the name is unknown ErrFindGender is returned.
+func (f *GenderFetcher) Fetch(ctx context.Context, name string) ([]byte, error) {
+	data, err := get(ctx, f.api, name)
 	if err != nil {
 		return nil, err
 	}
@@ -25,11 +30,36 @@ func (*GenderFetcher) Fetch(name string) ([]byte, error) {
 		return nil, err
 	}
 
-	return []byte(fmt.Sprintf(`{"gender": "%s"}`, resp.Gender)), nil
+	return genderFromResponse(resp)
+}
+
+// FetchBatch returns the responses from https://api.genderize.io?name[]=name
+// requested by batches of MaxBatchSize names.
+func (f *GenderFetcher) FetchBatch(ctx context.Context, names []string) ([]Result, error) {
+	return fetchNames(ctx, f.api, names, genderFromResponse)
+}
+
+func genderFromResponse(resp genderizeResponse) ([]byte, error) {
+	if resp.Gender == "" {
+		return nil, ErrFindGender
+	}
+
+	return json.Marshal(&genderResult{
+		Gender:      resp.Gender,
+		Probability: resp.Probability,
+		Count:       resp.Count,
+	})
 }
 
 type genderizeResponse struct {
-	Count  int    `json:"count"`
-	Name   string `json:"name"`
-	Gender string `json:"gender"`
+	Count       int     `json:"count"`
+	Name        string  `json:"name"`
+	Gender      string  `json:"gender"`
+	Probability float64 `json:"probability"`
+}
+
+type genderResult struct {
+	Gender      string  `json:"gender"`
+	Probability float64 `json:"genderProbability"`
+	Count       int     `json:"genderCount"`
 }
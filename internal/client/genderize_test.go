@@ -1,11 +1,16 @@
 package client
 
 import (
+	"context"
 	"testing"
 )
 
 func TestGenderFetcher_Fetch(t *testing.T) {
-	fetcher := NewGenderFetcher()
+	srv := newStubAPI(t, map[string]string{
+		"Ivan":  `{"count":1000,"name":"Ivan","gender":"male","probability":0.99}`,
+		"Xyzzy": `{"count":0,"name":"Xyzzy","gender":null,"probability":0}`,
+	})
+	fetcher := NewGenderFetcher(WithBaseURL(srv.URL))
 	type args struct {
 		name string
 	}
@@ -14,6 +19,11 @@ func TestGenderFetcher_Fetch(t *testing.T) {
 		args    args
 		wantErr bool
 	}{
+		{
+			name:    "unknown name",
+			args:    args{name: "Xyzzy"},
+			wantErr: true,
+		},
 		{
 			name:    "with name",
 			args:    args{name: "Ivan"},
@@ -27,7 +37,7 @@ func TestGenderFetcher_Fetch(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := fetcher.Fetch(tt.args.name)
+			_, err := fetcher.Fetch(context.Background(), tt.args.name)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GenderFetcher.Fetch() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -2,32 +2,36 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Fetcher is an autogenerated mock type for the Fetcher type
 type Fetcher struct {
 	mock.Mock
 }
 
-// Fetch provides a mock function with given fields: name
-func (_m *Fetcher) Fetch(name string) ([]byte, error) {
-	ret := _m.Called(name)
+// Fetch provides a mock function with given fields: ctx, name
+func (_m *Fetcher) Fetch(ctx context.Context, name string) ([]byte, error) {
+	ret := _m.Called(ctx, name)
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) ([]byte, error)); ok {
-		return rf(name)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, name)
 	}
-	if rf, ok := ret.Get(0).(func(string) []byte); ok {
-		r0 = rf(name)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(name)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
 	} else {
 		r1 = ret.Error(1)
 	}
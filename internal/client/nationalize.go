@@ -1,21 +1,39 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+
+	"github.com/insan1a/exile/internal/models"
 )
 
-const nationalizeURL = "https://api.nationalize.io"
+const (
+	nationalizeURL = "https://api.nationalize.io"
 
-type NationalityFetcher struct{}
+	// DefaultNationalityCandidates is the default count of the nationality candidates kept.
+	DefaultNationalityCandidates = 5
+)
 
-func NewNationalityFetcher() *NationalityFetcher {
-	return &NationalityFetcher{}
+type NationalityFetcher struct {
+	api        *api
+	candidates int
+}
+
+// NewNationalityFetcher returns the fetcher of the public API configured by the options.
+func NewNationalityFetcher(opts ...Option) *NationalityFetcher {
+	return &NationalityFetcher{
+		api:        newAPI(nationalizeURL, opts...),
+		candidates: DefaultNationalityCandidates,
+	}
 }
 
 // Fetch retuns the response from https://api.nationalize.io?name=name
-func (*NationalityFetcher) Fetch(name string) ([]byte, error) {
-	data, err := get(nationalizeURL, name)
+//
+// The most probable country is returned as the nationality
+// and the top candidates are returned as the nationalities.
+// If the nationality of the name is unknown ErrFindNationality is returned.
+func (f *NationalityFetcher) Fetch(ctx context.Context, name string) ([]byte, error) {
+	data, err := get(ctx, f.api, name)
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +43,39 @@ func (*NationalityFetcher) Fetch(name string) ([]byte, error) {
 		return nil, err
 	}
 
-	return []byte(fmt.Sprintf(`{"nationality":"%s"}`, resp.Country[0].ID)), nil
+	return f.fromResponse(resp)
+}
+
+// FetchBatch returns the responses from https://api.nationalize.io?name[]=name
+// requested by batches of MaxBatchSize names.
+func (f *NationalityFetcher) FetchBatch(ctx context.Context, names []string) ([]Result, error) {
+	return fetchNames(ctx, f.api, names, f.fromResponse)
+}
+
+func (f *NationalityFetcher) fromResponse(resp nationalizeResponse) ([]byte, error) {
+	if len(resp.Country) == 0 {
+		return nil, ErrFindNationality
+	}
+
+	candidates := resp.Country
+	if len(candidates) > f.candidates {
+		candidates = candidates[:f.candidates]
+	}
+
+	result := nationalityResult{
+		Nationality:   candidates[0].ID,
+		Probability:   candidates[0].Probability,
+		Count:         resp.Count,
+		Nationalities: make([]models.Nationality, len(candidates)),
+	}
+	for i, c := range candidates {
+		result.Nationalities[i] = models.Nationality{
+			CountryID:   c.ID,
+			Probability: c.Probability,
+		}
+	}
+
+	return json.Marshal(&result)
 }
 
 type nationalizeResponse struct {
@@ -38,3 +88,10 @@ type nationality struct {
 	ID          string  `json:"country_id"`
 	Probability float64 `json:"probability"`
 }
+
+type nationalityResult struct {
+	Nationality   string               `json:"nationality"`
+	Probability   float64              `json:"nationalityProbability"`
+	Count         int                  `json:"nationalityCount"`
+	Nationalities []models.Nationality `json:"nationalities"`
+}
@@ -1,11 +1,16 @@
 package client
 
 import (
+	"context"
 	"testing"
 )
 
 func TestNationalityFetcher_Fetch(t *testing.T) {
-	fetcher := NewNationalityFetcher()
+	srv := newStubAPI(t, map[string]string{
+		"Ivan":  `{"count":1000,"name":"Ivan","country":[{"country_id":"RU","probability":0.7}]}`,
+		"Xyzzy": `{"count":0,"name":"Xyzzy","country":[]}`,
+	})
+	fetcher := NewNationalityFetcher(WithBaseURL(srv.URL))
 	type args struct {
 		name string
 	}
@@ -14,6 +19,11 @@ func TestNationalityFetcher_Fetch(t *testing.T) {
 		args    args
 		wantErr bool
 	}{
+		{
+			name:    "unknown name",
+			args:    args{name: "Xyzzy"},
+			wantErr: true,
+		},
 		{
 			name:    "with name",
 			args:    args{name: "Ivan"},
@@ -27,7 +37,7 @@ func TestNationalityFetcher_Fetch(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := fetcher.Fetch(tt.args.name)
+			_, err := fetcher.Fetch(context.Background(), tt.args.name)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NationalityFetcher.Fetch() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,259 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/insan1a/exile/internal/models"
+)
+
+var (
+	ErrNilEnricher       = errors.New("the enricher is nil")
+	ErrDuplicateProvider = errors.New("the provider is already registered")
+)
+
+// Registry runs the registered enrichers concurrently.
+//
+// If several enrichers fill the same field,
+// the value of the succeeded one registered first is used.
+type Registry struct {
+	enrichers []Enricher
+}
+
+// NewRegistry returns the registry with given enrichers.
+func NewRegistry(enrichers ...Enricher) (*Registry, error) {
+	r := &Registry{}
+
+	for _, e := range enrichers {
+		if err := r.Register(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Register adds the enricher to the end of the registry.
+//
+// If enricher is nil ErrNilEnricher is returned.
+// If the provider with the same name is registered ErrDuplicateProvider is returned.
+func (r *Registry) Register(e Enricher) error {
+	if e == nil {
+		return ErrNilEnricher
+	}
+
+	for _, registered := range r.enrichers {
+		if registered.Name() == e.Name() {
+			return fmt.Errorf("%w: %s", ErrDuplicateProvider, e.Name())
+		}
+	}
+
+	r.enrichers = append(r.enrichers, e)
+	return nil
+}
+
+// Only returns the registry of the registered enrichers of given providers in the same order,
+// the unknown providers are ignored.
+func (r *Registry) Only(providers ...string) *Registry {
+	only := &Registry{}
+	for _, e := range r.enrichers {
+		if slices.Contains(providers, e.Name()) {
+			only.enrichers = append(only.enrichers, e)
+		}
+	}
+
+	return only
+}
+
+// Name returns the name of the registry.
+func (r *Registry) Name() string {
+	return "registry"
+}
+
+// Fields returns the fields filled by all the registered enrichers.
+func (r *Registry) Fields() []Field {
+	seen := make(map[Field]bool)
+	fields := make([]Field, 0)
+
+	for _, e := range r.enrichers {
+		for _, f := range e.Fields() {
+			if !seen[f] {
+				seen[f] = true
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	return fields
+}
+
+// Enrich runs all the enrichers concurrently and fills the person
+// with the fields declared by each of them.
+//
+// If some of the enrichers fail the person is filled by the succeeded ones
+// and the joined errors of the failed ones are returned.
+func (r *Registry) Enrich(ctx context.Context, p *models.Person) error {
+	results := make([]models.Person, len(r.enrichers))
+	errs := make([]error, len(r.enrichers))
+
+	var wg sync.WaitGroup
+	for i, e := range r.enrichers {
+		i, e := i, e
+		results[i] = *p
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = e.Enrich(ctx, &results[i])
+		}()
+	}
+	wg.Wait()
+
+	return r.merge(p, results, errs)
+}
+
+// EnrichBatch runs all the enrichers concurrently on the whole batch
+// and fills each person with the fields declared by each of them.
+//
+// If some of the enrichers fail for the person it is filled by the succeeded ones
+// and the joined errors of the failed ones are returned for it.
+func (r *Registry) EnrichBatch(ctx context.Context, people []*models.Person) []error {
+	results := make([][]models.Person, len(r.enrichers))
+	errs := make([][]error, len(r.enrichers))
+
+	var wg sync.WaitGroup
+	for i, e := range r.enrichers {
+		i, e := i, e
+
+		results[i] = make([]models.Person, len(people))
+		batch := make([]*models.Person, len(people))
+		for j, p := range people {
+			results[i][j] = *p
+			batch[j] = &results[i][j]
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = EnrichBatch(ctx, e, batch)
+		}()
+	}
+	wg.Wait()
+
+	personErrs := make([]error, len(people))
+	for j, p := range people {
+		personResults := make([]models.Person, len(r.enrichers))
+		enricherErrs := make([]error, len(r.enrichers))
+		for i := range r.enrichers {
+			personResults[i] = results[i][j]
+			enricherErrs[i] = errs[i][j]
+		}
+
+		personErrs[j] = r.merge(p, personResults, enricherErrs)
+	}
+
+	return personErrs
+}
+
+// merge fills the person with the fields of the results of the succeeded enrichers,
+// the field is taken from the first succeeded enricher declaring it.
+func (r *Registry) merge(p *models.Person, results []models.Person, errs []error) error {
+	var failed []error
+
+	filled := make(map[Field]bool)
+	for i, e := range r.enrichers {
+		if errs[i] != nil {
+			failed = append(failed, &ProviderError{Provider: e.Name(), Err: errs[i]})
+			continue
+		}
+
+		for _, f := range e.Fields() {
+			if filled[f] {
+				continue
+			}
+
+			filled[f] = true
+			CopyField(p, &results[i], f)
+		}
+	}
+
+	return errors.Join(failed...)
+}
+
+// ProviderError is the error of the provider failed to enrich the person.
+type ProviderError struct {
+	Provider string
+	Err      error
+}
+
+// Error returns the error prefixed by the name of the provider.
+func (e *ProviderError) Error() string {
+	return e.Provider + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error of the provider.
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// ProviderErrors returns the errors of the providers wrapped or joined into the error.
+func ProviderErrors(err error) []*ProviderError {
+	switch e := err.(type) {
+	case *ProviderError:
+		return []*ProviderError{e}
+	case interface{ Unwrap() []error }:
+		var errs []*ProviderError
+		for _, joined := range e.Unwrap() {
+			errs = append(errs, ProviderErrors(joined)...)
+		}
+		return errs
+	case interface{ Unwrap() error }:
+		return ProviderErrors(e.Unwrap())
+	}
+
+	return nil
+}
+
+// Filled reports whether the field of the person is filled.
+func Filled(p *models.Person, f Field) bool {
+	switch f {
+	case FieldAge:
+		return p.Age != 0
+	case FieldGender:
+		return p.Gender != ""
+	case FieldNationality:
+		return p.Nationality != ""
+	}
+
+	return false
+}
+
+// CopyField copies the field with its confidence data and its source from src to dst.
+func CopyField(dst, src *models.Person, f Field) {
+	if source, ok := src.Provenance[string(f)]; ok {
+		provenance := make(models.Provenance, len(dst.Provenance)+1)
+		for field, s := range dst.Provenance {
+			provenance[field] = s
+		}
+		provenance[string(f)] = source
+		dst.Provenance = provenance
+	}
+
+	switch f {
+	case FieldAge:
+		dst.Age = src.Age
+		dst.AgeCount = src.AgeCount
+	case FieldGender:
+		dst.Gender = src.Gender
+		dst.GenderProbability = src.GenderProbability
+		dst.GenderCount = src.GenderCount
+	case FieldNationality:
+		dst.Nationality = src.Nationality
+		dst.NationalityProbability = src.NationalityProbability
+		dst.NationalityCount = src.NationalityCount
+		dst.Nationalities = src.Nationalities
+	}
+}
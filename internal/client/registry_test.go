@@ -0,0 +1,193 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/insan1a/exile/internal/client"
+	"github.com/insan1a/exile/internal/client/mocks"
+	"github.com/insan1a/exile/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewRegistry(t *testing.T) {
+	tests := []struct {
+		name      string
+		enrichers []client.Enricher
+		wantErr   error
+	}{
+		{
+			name: "unique providers",
+			enrichers: []client.Enricher{
+				client.NewFetcherEnricher(client.ProviderAgify, mocks.NewFetcher(t), client.FieldAge),
+				client.NewFetcherEnricher(client.ProviderGenderize, mocks.NewFetcher(t), client.FieldGender),
+			},
+		},
+		{
+			name: "duplicate providers",
+			enrichers: []client.Enricher{
+				client.NewFetcherEnricher(client.ProviderAgify, mocks.NewFetcher(t), client.FieldAge),
+				client.NewFetcherEnricher(client.ProviderAgify, mocks.NewFetcher(t), client.FieldAge),
+			},
+			wantErr: client.ErrDuplicateProvider,
+		},
+		{
+			name:      "nil enricher",
+			enrichers: []client.Enricher{nil},
+			wantErr:   client.ErrNilEnricher,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.NewRegistry(tt.enrichers...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_Enrich(t *testing.T) {
+	agify := mocks.NewFetcher(t)
+	genderize := mocks.NewFetcher(t)
+	dictionary := mocks.NewFetcher(t)
+
+	registry, err := client.NewRegistry(
+		client.NewFetcherEnricher(client.ProviderAgify, agify, client.FieldAge),
+		client.NewFetcherEnricher(client.ProviderGenderize, genderize, client.FieldGender),
+		client.NewFetcherEnricher("dictionary", dictionary, client.FieldAge, client.FieldGender, client.FieldNationality),
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	agify.On("Fetch", mock.Anything, "Ivan").Once().Return([]byte(`{"age":40}`), nil)
+	genderize.On("Fetch", mock.Anything, "Ivan").Once().
+		Return([]byte(`{"gender":"male","genderProbability":0.99,"genderCount":1000}`), nil)
+	dictionary.On("Fetch", mock.Anything, "Ivan").Once().
+		Return([]byte(`{"age":30,"gender":"female","genderProbability":0.5,"nationality":"RU","nationalityProbability":0.7,`+
+			`"nationalities":[{"CountryID":"RU","Probability":0.7},{"CountryID":"UA","Probability":0.2}]}`), nil)
+
+	p := models.Person{Name: "Ivan"}
+	if err = registry.Enrich(context.Background(), &p); err != nil {
+		t.Fatalf("Registry.Enrich() error = %v", err)
+	}
+
+	wantSources := map[string]string{"age": "agify", "gender": "genderize", "nationality": "dictionary"}
+	if got := sources(p.Provenance); !reflect.DeepEqual(got, wantSources) {
+		t.Errorf("Registry.Enrich() sources = %v, want %v", got, wantSources)
+	}
+	p.Provenance = nil
+
+	want := models.Person{
+		Name:                   "Ivan",
+		Age:                    40,
+		Gender:                 "male",
+		GenderProbability:      0.99,
+		GenderCount:            1000,
+		Nationality:            "RU",
+		NationalityProbability: 0.7,
+		Nationalities: []models.Nationality{
+			{CountryID: "RU", Probability: 0.7},
+			{CountryID: "UA", Probability: 0.2},
+		},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("Registry.Enrich() person = %v, want %v", p, want)
+	}
+}
+
+func TestRegistry_EnrichBatch(t *testing.T) {
+	agify := mocks.NewBatchFetcher(t)
+	genderize := mocks.NewFetcher(t)
+
+	registry, err := client.NewRegistry(
+		client.NewFetcherEnricher(client.ProviderAgify, agify, client.FieldAge),
+		client.NewFetcherEnricher(client.ProviderGenderize, genderize, client.FieldGender),
+	)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	agify.On("FetchBatch", mock.Anything, []string{"Ivan", "Olga", "Xyzzy"}).Once().Return([]client.Result{
+		{Data: []byte(`{"age":40}`)},
+		{Data: []byte(`{"age":30}`)},
+		{Err: client.ErrFindAge},
+	}, nil)
+	genderize.On("Fetch", mock.Anything, "Ivan").Once().Return([]byte(`{"gender":"male"}`), nil)
+	genderize.On("Fetch", mock.Anything, "Olga").Once().Return([]byte(`{"gender":"female"}`), nil)
+	genderize.On("Fetch", mock.Anything, "Xyzzy").Once().Return([]byte(`{"gender":"male"}`), nil)
+
+	people := []*models.Person{{Name: "Ivan"}, {Name: "Olga"}, {Name: "Xyzzy"}}
+	errs := registry.EnrichBatch(context.Background(), people)
+
+	want := []models.Person{
+		{Name: "Ivan", Age: 40, Gender: "male"},
+		{Name: "Olga", Age: 30, Gender: "female"},
+	}
+	for i := range want {
+		if errs[i] != nil {
+			t.Fatalf("Registry.EnrichBatch() error = %v", errs[i])
+		}
+		wantSources := map[string]string{"age": "agify", "gender": "genderize"}
+		if got := sources(people[i].Provenance); !reflect.DeepEqual(got, wantSources) {
+			t.Errorf("Registry.EnrichBatch() sources = %v, want %v", got, wantSources)
+		}
+		people[i].Provenance = nil
+		if !reflect.DeepEqual(*people[i], want[i]) {
+			t.Errorf("Registry.EnrichBatch() person = %v, want %v", *people[i], want[i])
+		}
+	}
+
+	if !errors.Is(errs[2], client.ErrFindAge) {
+		t.Errorf("Registry.EnrichBatch() error = %v, want %v", errs[2], client.ErrFindAge)
+	}
+	if people[2].Gender != "male" {
+		t.Errorf("Registry.EnrichBatch() did not fill the partial result")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	providers := client.Providers()
+
+	enrichers, err := client.Select(providers, []string{client.ProviderNationalize, client.ProviderAgify})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+
+	if len(enrichers) != 2 || enrichers[0].Name() != client.ProviderNationalize || enrichers[1].Name() != client.ProviderAgify {
+		t.Errorf("Select() returned enrichers in wrong order")
+	}
+
+	if _, err = client.Select(providers, []string{"unknown"}); !errors.Is(err, client.ErrUnknownProvider) {
+		t.Errorf("Select() error = %v, wantErr %v", err, client.ErrUnknownProvider)
+	}
+}
+
+// sources returns the sources of the fields of the provenance.
+func sources(provenance models.Provenance) map[string]string {
+	result := make(map[string]string, len(provenance))
+	for f, s := range provenance {
+		result[f] = s.Source
+	}
+
+	return result
+}
+
+func TestRegistry_Only(t *testing.T) {
+	registry, err := client.NewRegistry(
+		client.NewFetcherEnricher(client.ProviderAgify, mocks.NewFetcher(t), client.FieldAge),
+		client.NewFetcherEnricher(client.ProviderGenderize, mocks.NewFetcher(t), client.FieldGender),
+		client.NewFetcherEnricher(client.ProviderNationalize, mocks.NewFetcher(t), client.FieldNationality),
+	)
+	if err != nil {
+		t.Fatalf("client.NewRegistry() error = %v", err)
+	}
+
+	got := registry.Only(client.ProviderNationalize, client.ProviderAgify, "unknown").Fields()
+	if want := []client.Field{client.FieldAge, client.FieldNationality}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Registry.Only() fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/insan1a/exile/internal/client"
+	"github.com/insan1a/exile/internal/lib/stubapi"
+	"github.com/insan1a/exile/internal/models"
+)
+
+// stubRegistry returns the registry of the providers querying the stub APIs.
+func stubRegistry(t *testing.T, opts ...stubapi.Option) *client.Registry {
+	t.Helper()
+
+	_, baseURL := stubapi.Start(t, opts...)
+
+	enrichers, err := client.Select(client.Providers(
+		client.WithAPI(client.ProviderAgify, client.WithBaseURL(baseURL+stubapi.PathAgify)),
+		client.WithAPI(client.ProviderGenderize, client.WithBaseURL(baseURL+stubapi.PathGenderize)),
+		client.WithAPI(client.ProviderNationalize, client.WithBaseURL(baseURL+stubapi.PathNationalize)),
+	), []string{client.ProviderAgify, client.ProviderGenderize, client.ProviderNationalize})
+	if err != nil {
+		t.Fatalf("client.Select() error = %v", err)
+	}
+
+	registry, err := client.NewRegistry(enrichers...)
+	if err != nil {
+		t.Fatalf("client.NewRegistry() error = %v", err)
+	}
+
+	return registry
+}
+
+func TestRegistry_StubAPI(t *testing.T) {
+	registry := stubRegistry(t, stubapi.WithNames(map[string]stubapi.Entry{
+		"Ivan": {
+			Age:               40,
+			Gender:            "male",
+			GenderProbability: 0.99,
+			Countries:         []stubapi.Country{{ID: "RU", Probability: 0.5}, {ID: "UA", Probability: 0.2}},
+			Count:             10,
+		},
+	}))
+
+	people := []*models.Person{{Name: "Ivan"}, {Name: "Xyzzy"}}
+	errs := registry.EnrichBatch(context.Background(), people)
+
+	if errs[0] != nil {
+		t.Fatalf("Registry.EnrichBatch() error = %v", errs[0])
+	}
+	if p := people[0]; p.Age != 40 || p.Gender != "male" || p.Nationality != "RU" || len(p.Nationalities) != 2 {
+		t.Errorf("Registry.EnrichBatch() = %+v", *p)
+	}
+
+	for _, target := range []error{client.ErrFindAge, client.ErrFindGender, client.ErrFindNationality} {
+		if !errors.Is(errs[1], target) {
+			t.Errorf("Registry.EnrichBatch() error = %v, want %v", errs[1], target)
+		}
+	}
+}
+
+func TestRegistry_StubAPIRateLimit(t *testing.T) {
+	registry := stubRegistry(t, stubapi.WithLimit(1, time.Hour))
+
+	if err := registry.Enrich(context.Background(), &models.Person{Name: "Ivan"}); err != nil {
+		t.Fatalf("Registry.Enrich() error = %v", err)
+	}
+
+	err := registry.Enrich(context.Background(), &models.Person{Name: "Olga"})
+	if wait, ok := client.RetryAfter(err); !ok || wait < time.Hour-time.Minute {
+		t.Errorf("Registry.Enrich() error = %v, want the rate limit error", err)
+	}
+}
+
+func TestRegistry_StubAPILatency(t *testing.T) {
+	registry := stubRegistry(t, stubapi.WithLatency(time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := registry.Enrich(ctx, &models.Person{Name: "Ivan"}); !client.IsTransient(err) {
+		t.Errorf("Registry.Enrich() error = %v, want the transient error", err)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"os"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
@@ -18,14 +19,25 @@ type APIConfig struct {
 	DatabaseURL string `env:"DATABASE_URL"`
 	CacheURL    string `env:"CACHE_URL"`
 
+	// NameScripts are the Unicode scripts of the letters allowed in the names
+	// and NamePunctuation are the characters joining their parts like "Rimsky-Korsakov".
+	NameScripts     []string `env:"NAME_SCRIPTS" env-default:"Latin,Cyrillic"`
+	NamePunctuation string   `env:"NAME_PUNCTUATION" env-default:"-'’ "`
+
 	KafkaMap         kafka.ConfigMap
 	BootstrapServers string `env:"KAFKA_BOOTSTRAP_SERVERS"`
 	Topic            string `env:"KAFKA_PRODUCER_TOPIC"`
+
+	ReplyKafkaMap kafka.ConfigMap
+	ReplyTopic    string        `env:"KAFKA_REPLY_TOPIC"`
+	ReplyGroupID  string        `env:"KAFKA_REPLY_GROUP_ID"`
+	ReplyTimeout  time.Duration `env:"REPLY_TIMEOUT" env-default:"3s"`
 }
 
 func LoadAPIConfig() (*APIConfig, error) {
 	cfg := APIConfig{
-		KafkaMap: kafka.ConfigMap{},
+		KafkaMap:      kafka.ConfigMap{},
+		ReplyKafkaMap: kafka.ConfigMap{},
 	}
 	if err := cleanenv.ReadEnv(&cfg); err != nil {
 		return nil, err
@@ -33,5 +45,16 @@ func LoadAPIConfig() (*APIConfig, error) {
 
 	cfg.KafkaMap["bootstrap.servers"] = cfg.BootstrapServers
 
+	// Every instance of the api must read all the replies,
+	// so each of them uses its own consumer group.
+	if cfg.ReplyGroupID == "" {
+		hostname, _ := os.Hostname()
+		cfg.ReplyGroupID = "exile-api-" + hostname
+	}
+
+	cfg.ReplyKafkaMap["bootstrap.servers"] = cfg.BootstrapServers
+	cfg.ReplyKafkaMap["group.id"] = cfg.ReplyGroupID
+	cfg.ReplyKafkaMap["auto.offset.reset"] = "latest"
+
 	return &cfg, nil
 }
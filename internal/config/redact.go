@@ -0,0 +1,31 @@
+package config
+
+import (
+	"net/url"
+)
+
+const redacted = "[REDACTED]"
+
+// redactSecret hides the non-empty secret.
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+// redactURL hides the password of the URL,
+// the value which is not the URL like the key-value DSN is hidden entirely.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return redacted
+	}
+
+	return u.Redacted()
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// ReenrichConfig is the config of the re-enrichment of the stored people,
+// the providers and the cache are configured by the same variables as in ServiceConfig.
+type ReenrichConfig struct {
+	Env string `env:"ENV" env-default:"dev"`
+
+	DatabaseURL string `env:"DATABASE_URL"`
+	CacheURL    string `env:"CACHE_URL"`
+
+	Enrichers []string `env:"ENRICHERS" env-default:"agify,genderize,nationalize"`
+
+	AgifyURL         string        `env:"AGIFY_URL"`
+	GenderizeURL     string        `env:"GENDERIZE_URL"`
+	NationalizeURL   string        `env:"NATIONALIZE_URL"`
+	ProvidersAPIKey  string        `env:"PROVIDERS_API_KEY"`
+	ProvidersTimeout time.Duration `env:"PROVIDERS_TIMEOUT" env-default:"5s"`
+
+	// DatasetPath is the CSV or JSON file of the names the providers look up
+	// instead of the APIs in the primary DatasetMode or if the APIs fail in the fallback one.
+	// The modified file is reloaded every DatasetReloadInterval.
+	DatasetPath           string        `env:"DATASET_PATH"`
+	DatasetMode           string        `env:"DATASET_MODE" env-default:"fallback"`
+	DatasetReloadInterval time.Duration `env:"DATASET_RELOAD_INTERVAL" env-default:"30s"`
+
+	EnrichmentCacheTTL         time.Duration `env:"ENRICHMENT_CACHE_TTL" env-default:"24h"`
+	EnrichmentCacheNegativeTTL time.Duration `env:"ENRICHMENT_CACHE_NEGATIVE_TTL" env-default:"1h"`
+	EnrichmentCacheSize        int           `env:"ENRICHMENT_CACHE_SIZE" env-default:"10000"`
+
+	// The people missing any of the enriched fields or enriched longer than StaleAfter ago
+	// are enriched again by batches of BatchSize people, each batch is enriched no longer than Timeout.
+	StaleAfter time.Duration `env:"REENRICH_STALE_AFTER" env-default:"720h"`
+	BatchSize  int           `env:"REENRICH_BATCH_SIZE" env-default:"100"`
+	Timeout    time.Duration `env:"REENRICH_TIMEOUT" env-default:"10s"`
+	// Job is the name the progress is stored by, the interrupted pass of the job is resumed.
+	Job string `env:"REENRICH_JOB" env-default:"reenrich"`
+}
+
+// LogValue logs the config without the secrets.
+func (c ReenrichConfig) LogValue() slog.Value {
+	type config ReenrichConfig
+
+	c.DatabaseURL = redactURL(c.DatabaseURL)
+	c.CacheURL = redactURL(c.CacheURL)
+	c.ProvidersAPIKey = redactSecret(c.ProvidersAPIKey)
+
+	return slog.AnyValue(config(c))
+}
+
+func LoadReenrichConfig() (*ReenrichConfig, error) {
+	var cfg ReenrichConfig
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
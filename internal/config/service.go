@@ -1,6 +1,7 @@
 package config
 
 import (
+	"log/slog"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
@@ -11,16 +12,84 @@ type ServiceConfig struct {
 	Env string `env:"ENV" env-default:"dev"`
 
 	DatabaseURL string `env:"DATABASE_URL"`
+	CacheURL    string `env:"CACHE_URL"`
+	MetricsPort string `env:"METRICS_PORT"`
+
+	// NameScripts are the Unicode scripts of the letters allowed in the names
+	// and NamePunctuation are the characters joining their parts like "Rimsky-Korsakov".
+	NameScripts     []string `env:"NAME_SCRIPTS" env-default:"Latin,Cyrillic"`
+	NamePunctuation string   `env:"NAME_PUNCTUATION" env-default:"-'’ "`
+
+	// Enrichers is the ordered list of the providers enriching the person.
+	// If several providers fill the same field, the first one wins.
+	Enrichers []string `env:"ENRICHERS" env-default:"agify,genderize,nationalize"`
+
+	// EnrichmentPolicies are the policies of the providers by their names
+	// in the form provider:policy, the providers without the policy are required.
+	EnrichmentPolicies map[string]string `env:"ENRICHMENT_POLICIES"`
+	// The people stored without the fields of the optional providers
+	// are enriched again every ReenrichInterval by batches of ReenrichBatchSize people,
+	// the zero interval disables the re-enrichment.
+	ReenrichInterval  time.Duration `env:"REENRICH_INTERVAL" env-default:"0"`
+	ReenrichBatchSize int           `env:"REENRICH_BATCH_SIZE" env-default:"100"`
+
+	// The base URLs of the providers, the public APIs are used if empty.
+	AgifyURL       string `env:"AGIFY_URL"`
+	GenderizeURL   string `env:"GENDERIZE_URL"`
+	NationalizeURL string `env:"NATIONALIZE_URL"`
+	// ProvidersAPIKey is the key of the paid tier shared by the providers.
+	ProvidersAPIKey  string        `env:"PROVIDERS_API_KEY"`
+	ProvidersTimeout time.Duration `env:"PROVIDERS_TIMEOUT" env-default:"5s"`
+
+	// DatasetPath is the CSV or JSON file of the names the providers look up
+	// instead of the APIs in the primary DatasetMode or if the APIs fail in the fallback one.
+	// The modified file is reloaded every DatasetReloadInterval.
+	DatasetPath           string        `env:"DATASET_PATH"`
+	DatasetMode           string        `env:"DATASET_MODE" env-default:"fallback"`
+	DatasetReloadInterval time.Duration `env:"DATASET_RELOAD_INTERVAL" env-default:"30s"`
+
+	// The responses of the providers are cached in redis if CacheURL is set,
+	// otherwise in memory.
+	EnrichmentCacheTTL         time.Duration `env:"ENRICHMENT_CACHE_TTL" env-default:"24h"`
+	EnrichmentCacheNegativeTTL time.Duration `env:"ENRICHMENT_CACHE_NEGATIVE_TTL" env-default:"1h"`
+	EnrichmentCacheSize        int           `env:"ENRICHMENT_CACHE_SIZE" env-default:"10000"`
+
+	// The consumed messages are enriched by batches of up to BatchSize messages
+	// collected no longer than BatchWait.
+	BatchSize int           `env:"BATCH_SIZE" env-default:"10"`
+	BatchWait time.Duration `env:"BATCH_WAIT" env-default:"200ms"`
+
+	// The transient enrichment failures are retried in-process with exponential backoff,
+	// then sent to the retry topic up to RetryTopicAttempts times.
+	RetryAttempts      int           `env:"RETRY_ATTEMPTS" env-default:"3"`
+	RetryBackoff       time.Duration `env:"RETRY_BACKOFF" env-default:"200ms"`
+	RetryMaxBackoff    time.Duration `env:"RETRY_MAX_BACKOFF" env-default:"2s"`
+	RetryTopicAttempts int           `env:"RETRY_TOPIC_ATTEMPTS" env-default:"5"`
+	RetryTopicDelay    time.Duration `env:"RETRY_TOPIC_DELAY" env-default:"30s"`
+	RetryTopicMaxDelay time.Duration `env:"RETRY_TOPIC_MAX_DELAY" env-default:"10m"`
 
 	KafkaMap         kafka.ConfigMap
 	GroupID          string        `env:"KAFKA_GROUP_ID"`
 	BootstrapServers string        `env:"KAFKA_BOOTSTRAP_SERVERS"`
 	AutoOffsetReset  string        `env:"KAFKA_AUTO_OFFSET_RESET"`
 	Topic            string        `env:"KAFKA_PRODUCER_TOPIC"`
+	ReplyTopic       string        `env:"KAFKA_REPLY_TOPIC"`
+	RetryTopic       string        `env:"KAFKA_RETRY_TOPIC"`
 	Topics           []string      `env:"KAFKA_CONSUMER_TOPICS"`
 	Timeout          time.Duration `env:"KAFKA_TIMEOUT" env-default:"100ms"`
 }
 
+// LogValue logs the config without the secrets.
+func (c ServiceConfig) LogValue() slog.Value {
+	type config ServiceConfig
+
+	c.DatabaseURL = redactURL(c.DatabaseURL)
+	c.CacheURL = redactURL(c.CacheURL)
+	c.ProvidersAPIKey = redactSecret(c.ProvidersAPIKey)
+
+	return slog.AnyValue(config(c))
+}
+
 func LoadServiceConfig() (*ServiceConfig, error) {
 	cfg := ServiceConfig{
 		KafkaMap: kafka.ConfigMap{},
@@ -31,6 +100,8 @@ func LoadServiceConfig() (*ServiceConfig, error) {
 
 	cfg.KafkaMap["group.id"] = cfg.GroupID
 	cfg.KafkaMap["auto.offset.reset"] = cfg.AutoOffsetReset
+	// The offsets are stored by the service once the messages are processed.
+	cfg.KafkaMap["enable.auto.offset.store"] = false
 	cfg.KafkaMap["bootstrap.servers"] = cfg.BootstrapServers
 
 	return &cfg, nil
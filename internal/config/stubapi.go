@@ -0,0 +1,32 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// StubAPIConfig is the config of the server emulating the APIs of the providers.
+type StubAPIConfig struct {
+	Env string `env:"ENV" env-default:"dev"`
+
+	Port string `env:"PORT" env-default:"8090"`
+
+	// Each API answers Limit names per Window, the zero limit disables it.
+	Limit  int           `env:"LIMIT" env-default:"1000"`
+	Window time.Duration `env:"WINDOW" env-default:"24h"`
+	// Latency delays every response, the FailureRate part of the requests fails with 503.
+	Latency     time.Duration `env:"LATENCY" env-default:"0s"`
+	FailureRate float64       `env:"FAILURE_RATE" env-default:"0"`
+	// APIKey is required in the apikey param if set.
+	APIKey string `env:"API_KEY"`
+}
+
+func LoadStubAPIConfig() (*StubAPIConfig, error) {
+	var cfg StubAPIConfig
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
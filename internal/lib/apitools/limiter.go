@@ -0,0 +1,58 @@
+package apitools
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter tracks the rate limit of the API reported by its responses.
+type Limiter struct {
+	mu        sync.Mutex
+	known     bool
+	remaining int64
+	resetAt   time.Time
+}
+
+// NewLimiter returns the limiter allowing any request until the rate limit is known.
+func NewLimiter() *Limiter {
+	return &Limiter{}
+}
+
+// Update stores the rate limit reported by the response.
+func (l *Limiter) Update(rl *RateLimit) {
+	if rl == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.known = true
+	l.remaining = rl.Remaining
+	l.resetAt = time.Now().Add(time.Duration(rl.Reset) * time.Second)
+}
+
+// Reserve takes n names from the quota of the current time window.
+//
+// If the quota is exhausted false and the time until the window resets are returned.
+func (l *Limiter) Reserve(n int64) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.known {
+		return 0, true
+	}
+
+	wait := time.Until(l.resetAt)
+	if wait <= 0 {
+		l.known = false
+		return 0, true
+	}
+
+	if l.remaining < n {
+		return wait, false
+	}
+
+	l.remaining -= n
+	return 0, true
+}
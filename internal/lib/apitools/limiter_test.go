@@ -0,0 +1,34 @@
+package apitools
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_Reserve(t *testing.T) {
+	l := NewLimiter()
+
+	if _, ok := l.Reserve(100); !ok {
+		t.Fatalf("Limiter.Reserve() = false with unknown rate limit")
+	}
+
+	l.Update(&RateLimit{Limit: 1000, Remaining: 10, Reset: 60})
+
+	if _, ok := l.Reserve(10); !ok {
+		t.Fatalf("Limiter.Reserve() = false with remaining quota")
+	}
+
+	wait, ok := l.Reserve(1)
+	if ok {
+		t.Fatalf("Limiter.Reserve() = true with exhausted quota")
+	}
+	if wait <= 0 || wait > time.Minute {
+		t.Errorf("Limiter.Reserve() wait = %v, want up to a minute", wait)
+	}
+
+	l.Update(&RateLimit{Limit: 1000, Remaining: 0, Reset: 0})
+
+	if _, ok = l.Reserve(1); !ok {
+		t.Errorf("Limiter.Reserve() = false after the window reset")
+	}
+}
@@ -0,0 +1,121 @@
+package personname
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrUnknownScript = errors.New("the script is unknown")
+
+// DefaultRules allow the Latin and Cyrillic letters joined by the hyphens, apostrophes and spaces,
+// like "Rimsky-Korsakov", "O'Brien" or "Мария Анна".
+var DefaultRules = Rules{
+	Scripts:     []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic},
+	Punctuation: "-'’ ",
+}
+
+// Rules are the letters and the punctuation allowed in the names.
+type Rules struct {
+	Scripts []*unicode.RangeTable
+	// Punctuation are the characters joining the parts of the name,
+	// they are allowed only between the letters and never in a row.
+	Punctuation string
+}
+
+// NewRules returns the rules of the scripts named like in unicode.Scripts, e.g. "Latin" or "Cyrillic".
+//
+// If the script is unknown returns ErrUnknownScript.
+func NewRules(scripts []string, punctuation string) (Rules, error) {
+	r := Rules{Punctuation: punctuation}
+	for _, name := range scripts {
+		script, ok := unicode.Scripts[strings.TrimSpace(name)]
+		if !ok {
+			return Rules{}, fmt.Errorf("NewRules: %w: %q", ErrUnknownScript, name)
+		}
+		r.Scripts = append(r.Scripts, script)
+	}
+
+	return r, nil
+}
+
+// Valid reports whether the name consists of the letters of the scripts and the marks of the letters
+// joined by the single punctuation characters.
+func (r Rules) Valid(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	punct := true
+	for _, c := range name {
+		switch {
+		case unicode.Is(unicode.Mn, c):
+			if punct {
+				return false
+			}
+		case unicode.IsLetter(c):
+			if !unicode.In(c, r.Scripts...) {
+				return false
+			}
+			punct = false
+		case strings.ContainsRune(r.Punctuation, c):
+			if punct {
+				return false
+			}
+			punct = true
+		default:
+			return false
+		}
+	}
+
+	return !punct
+}
+
+// Normalize trims the name, collapses its spaces and capitalizes each of its parts,
+// the parts of the mixed case like "McDonald" are kept as is.
+func Normalize(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = capitalize(word)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// capitalize capitalizes the parts of the word separated by the punctuation.
+func capitalize(word string) string {
+	var b strings.Builder
+
+	start := 0
+	runes := []rune(word)
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.Is(unicode.Mn, runes[i])) {
+			continue
+		}
+
+		b.WriteString(capitalizePart(runes[start:i]))
+		if i < len(runes) {
+			b.WriteRune(runes[i])
+		}
+		start = i + 1
+	}
+
+	return b.String()
+}
+
+func capitalizePart(part []rune) string {
+	if len(part) == 0 {
+		return ""
+	}
+
+	s := string(part)
+	if s == strings.ToLower(s) || s == strings.ToUpper(s) {
+		s = strings.ToLower(s)
+	}
+
+	part = []rune(s)
+	part[0] = unicode.ToTitle(part[0])
+
+	return string(part)
+}
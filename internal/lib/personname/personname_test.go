@@ -0,0 +1,84 @@
+package personname
+
+import (
+	"errors"
+	"testing"
+	"unicode"
+)
+
+func TestRules_Valid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		value string
+		want  bool
+	}{
+		{name: "latin", rules: DefaultRules, value: "Ivan", want: true},
+		{name: "cyrillic", rules: DefaultRules, value: "Иван", want: true},
+		{name: "hyphenated", rules: DefaultRules, value: "Rimsky-Korsakov", want: true},
+		{name: "apostrophe", rules: DefaultRules, value: "O'Brien", want: true},
+		{name: "spaces", rules: DefaultRules, value: "Мария Анна", want: true},
+		{name: "diacritics", rules: DefaultRules, value: "José", want: true},
+		{name: "combining mark", rules: DefaultRules, value: "Jose\u0301", want: true},
+		{name: "leading combining mark", rules: DefaultRules, value: "\u0301Jose", want: false},
+		{name: "empty", rules: DefaultRules, value: "", want: false},
+		{name: "digits", rules: DefaultRules, value: "Ivan2", want: false},
+		{name: "leading hyphen", rules: DefaultRules, value: "-Ivan", want: false},
+		{name: "trailing apostrophe", rules: DefaultRules, value: "Ivan'", want: false},
+		{name: "punctuation in a row", rules: DefaultRules, value: "Rimsky--Korsakov", want: false},
+		{name: "other script", rules: DefaultRules, value: "Γιάννης", want: false},
+		{
+			name:  "configured script",
+			rules: Rules{Scripts: []*unicode.RangeTable{unicode.Greek}, Punctuation: "-"},
+			value: "Γιάννης",
+			want:  true,
+		},
+		{
+			name:  "not configured punctuation",
+			rules: Rules{Scripts: []*unicode.RangeTable{unicode.Latin}, Punctuation: "-"},
+			value: "O'Brien",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Valid(tt.value); got != tt.want {
+				t.Errorf("Rules.Valid(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRules(t *testing.T) {
+	r, err := NewRules([]string{"Latin", " Greek"}, "-")
+	if err != nil {
+		t.Fatalf("NewRules() error = %v", err)
+	}
+	if !r.Valid("Γιάννης-John") {
+		t.Errorf("Rules.Valid() = false for the configured scripts")
+	}
+
+	if _, err = NewRules([]string{"Klingon"}, ""); !errors.Is(err, ErrUnknownScript) {
+		t.Errorf("NewRules() error = %v, want %v", err, ErrUnknownScript)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "  ivan  ", want: "Ivan"},
+		{value: "IVANOV", want: "Ivanov"},
+		{value: "мария   анна", want: "Мария Анна"},
+		{value: "rimsky-korsakov", want: "Rimsky-Korsakov"},
+		{value: "o'brien", want: "O'Brien"},
+		{value: "McDonald", want: "McDonald"},
+		{value: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.value); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
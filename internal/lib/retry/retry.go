@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Policy is the policy of retrying with exponential backoff.
+type Policy struct {
+	// Attempts is the maximum count of calls, the call is not retried if it is less than 2.
+	Attempts int
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+	// Max is the maximum delay between the calls.
+	Max time.Duration
+}
+
+// Delay returns the delay before the given retry counted from 1.
+//
+// The delay is doubled by each retry up to the maximum one.
+func (p Policy) Delay(retry int) time.Duration {
+	d := p.Initial
+	for i := 1; i < retry; i++ {
+		d *= 2
+		if p.Max > 0 && d >= p.Max {
+			return p.Max
+		}
+	}
+
+	if p.Max > 0 && d > p.Max {
+		return p.Max
+	}
+
+	return d
+}
+
+// Wait waits the delay before the given retry or until the context is done.
+func (p Policy) Wait(ctx context.Context, retry int) error {
+	timer := time.NewTimer(p.Delay(retry))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Do calls fn until it succeeds, returns the error not accepted by retryable
+// or the attempts of the policy are exhausted, the last error is returned.
+func Do(ctx context.Context, p Policy, retryable func(error) bool, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || !retryable(err) || attempt >= p.Attempts {
+			return err
+		}
+
+		if waitErr := p.Wait(ctx, attempt); waitErr != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Delay(t *testing.T) {
+	p := Policy{Initial: 100 * time.Millisecond, Max: time.Second}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{retry: 1, want: 100 * time.Millisecond},
+		{retry: 2, want: 200 * time.Millisecond},
+		{retry: 4, want: 800 * time.Millisecond},
+		{retry: 5, want: time.Second},
+		{retry: 100, want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.Delay(tt.retry); got != tt.want {
+			t.Errorf("Policy.Delay(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+	retryable := func(err error) bool { return errors.Is(err, errTransient) }
+	p := Policy{Attempts: 3, Initial: time.Millisecond}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "success after transient errors",
+			errs:      []error{errTransient, errTransient, nil},
+			wantCalls: 3,
+		},
+		{
+			name:      "attempts exhausted",
+			errs:      []error{errTransient, errTransient, errTransient, nil},
+			wantCalls: 3,
+			wantErr:   errTransient,
+		},
+		{
+			name:      "permanent error",
+			errs:      []error{errPermanent, nil},
+			wantCalls: 1,
+			wantErr:   errPermanent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), p, retryable, func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Do() calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
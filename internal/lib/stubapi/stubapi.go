@@ -0,0 +1,351 @@
+// Package stubapi emulates agify, genderize and nationalize
+// for the tests and the local development.
+package stubapi
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The paths of the emulated APIs, the base URL of the API is the URL of the server with its path.
+const (
+	PathAgify       = "/agify"
+	PathGenderize   = "/genderize"
+	PathNationalize = "/nationalize"
+)
+
+const (
+	// DefaultLimit is the count of names answered by each API per window.
+	DefaultLimit = 1000
+	// DefaultWindow is the period the quota is reset after.
+	DefaultWindow = 24 * time.Hour
+	// MaxBatchSize is the maximum count of the names in the request.
+	MaxBatchSize = 10
+)
+
+// countries are the nationalities of the generated names.
+var countries = []string{"RU", "UA", "BY", "KZ", "PL", "US", "GB", "DE", "FR", "HR"}
+
+// Entry is the data of the name answered by the APIs, the zero values are unknown.
+type Entry struct {
+	Age               int
+	Gender            string
+	GenderProbability float64
+	Countries         []Country
+	Count             int
+}
+
+// Country is the nationality candidate of the name.
+type Country struct {
+	ID          string  `json:"country_id"`
+	Probability float64 `json:"probability"`
+}
+
+type Option func(s *Server)
+
+// WithNames makes the server answer only the given names, the other names are unknown.
+// By default the data of any name is generated from its hash.
+func WithNames(names map[string]Entry) Option {
+	return func(s *Server) {
+		s.names = make(map[string]Entry, len(names))
+		for name, e := range names {
+			s.names[normalize(name)] = e
+		}
+	}
+}
+
+// WithLimit sets the count of names answered by each API per window, zero disables the limit.
+func WithLimit(limit int, window time.Duration) Option {
+	return func(s *Server) {
+		s.limit = limit
+		s.window = window
+	}
+}
+
+// WithLatency delays every response.
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// WithFailureRate makes the part of the requests fail with 503 Service Unavailable.
+func WithFailureRate(rate float64) Option {
+	return func(s *Server) {
+		s.failureRate = rate
+	}
+}
+
+// WithAPIKey makes the server reject the requests without the apikey param with given value.
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// Server emulates the response shapes, the rate limit headers
+// and the error bodies of agify, genderize and nationalize.
+type Server struct {
+	names       map[string]Entry
+	limit       int
+	window      time.Duration
+	latency     time.Duration
+	failureRate float64
+	apiKey      string
+
+	mu     sync.Mutex
+	quotas map[string]*quota
+}
+
+// quota is the count of names answered by the API in the current window.
+type quota struct {
+	used    int
+	resetAt time.Time
+	// requests is the count of all the requests to the API.
+	requests int
+}
+
+// New returns the server configured by the options.
+func New(opts ...Option) *Server {
+	s := &Server{
+		limit:  DefaultLimit,
+		window: DefaultWindow,
+		quotas: make(map[string]*quota),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Start starts the server with httptest and returns its URL,
+// the server is closed on the cleanup of the test.
+func Start(tb testing.TB, opts ...Option) (*Server, string) {
+	tb.Helper()
+
+	s := New(opts...)
+	srv := httptest.NewServer(s.Handler())
+	tb.Cleanup(srv.Close)
+
+	return s, srv.URL
+}
+
+// Handler returns the handler serving the APIs by their paths.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(PathAgify, s.api(PathAgify, s.agify))
+	mux.Handle(PathGenderize, s.api(PathGenderize, s.genderize))
+	mux.Handle(PathNationalize, s.api(PathNationalize, s.nationalize))
+
+	return mux
+}
+
+// Requests returns the count of the requests to the API with given path.
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if q, ok := s.quotas[path]; ok {
+		return q.requests
+	}
+
+	return 0
+}
+
+// api returns the handler answering the names of the request by the response of each name.
+func (s *Server) api(path string, respond func(name string) any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.latency > 0 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(s.latency):
+			}
+		}
+
+		query := r.URL.Query()
+		if s.apiKey != "" && query.Get("apikey") != s.apiKey {
+			writeError(w, http.StatusUnauthorized, "Invalid API key")
+			return
+		}
+
+		names, batch := query["name[]"], true
+		if len(names) == 0 {
+			names, batch = query["name"], false
+		}
+
+		if len(names) == 0 {
+			writeError(w, http.StatusUnprocessableEntity, "Missing 'name' parameter")
+			return
+		}
+		if len(names) > MaxBatchSize || (!batch && len(names) > 1) {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid 'name' parameter")
+			return
+		}
+
+		if !s.take(w, path, len(names)) {
+			writeError(w, http.StatusTooManyRequests, "Request limit reached")
+			return
+		}
+
+		if s.failureRate > 0 && rand.Float64() < s.failureRate {
+			writeError(w, http.StatusServiceUnavailable, "Service temporarily unavailable")
+			return
+		}
+
+		if !batch {
+			writeJSON(w, http.StatusOK, respond(names[0]))
+			return
+		}
+
+		resps := make([]any, len(names))
+		for i, name := range names {
+			resps[i] = respond(name)
+		}
+		writeJSON(w, http.StatusOK, resps)
+	})
+}
+
+// take takes the count of names from the quota of the API
+// and sets the rate limit headers, false is returned if the quota is exhausted.
+func (s *Server) take(w http.ResponseWriter, path string, names int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	q, ok := s.quotas[path]
+	if !ok {
+		q = &quota{resetAt: now.Add(s.window)}
+		s.quotas[path] = q
+	}
+	q.requests++
+
+	if s.limit == 0 {
+		return true
+	}
+
+	if !now.Before(q.resetAt) {
+		q.used = 0
+		q.resetAt = now.Add(s.window)
+	}
+
+	allowed := q.used+names <= s.limit
+	if allowed {
+		q.used += names
+	}
+
+	reset := int64(q.resetAt.Sub(now).Round(time.Second) / time.Second)
+	w.Header().Set("X-Rate-Limit-Limit", strconv.Itoa(s.limit))
+	w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(s.limit-q.used))
+	w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(reset, 10))
+
+	return allowed
+}
+
+func (s *Server) agify(name string) any {
+	e, _ := s.lookup(name)
+
+	resp := struct {
+		Count int    `json:"count"`
+		Name  string `json:"name"`
+		Age   *int   `json:"age"`
+	}{Count: e.Count, Name: name}
+	if e.Age != 0 {
+		resp.Age = &e.Age
+	}
+
+	return resp
+}
+
+func (s *Server) genderize(name string) any {
+	e, _ := s.lookup(name)
+
+	resp := struct {
+		Count       int     `json:"count"`
+		Name        string  `json:"name"`
+		Gender      *string `json:"gender"`
+		Probability float64 `json:"probability"`
+	}{Count: e.Count, Name: name, Probability: e.GenderProbability}
+	if e.Gender != "" {
+		resp.Gender = &e.Gender
+	}
+
+	return resp
+}
+
+func (s *Server) nationalize(name string) any {
+	e, _ := s.lookup(name)
+
+	resp := struct {
+		Count   int       `json:"count"`
+		Name    string    `json:"name"`
+		Country []Country `json:"country"`
+	}{Count: e.Count, Name: name, Country: e.Countries}
+	if resp.Country == nil {
+		resp.Country = []Country{}
+	}
+
+	return resp
+}
+
+// lookup returns the entry of the configured name or the entry generated from its hash.
+func (s *Server) lookup(name string) (Entry, bool) {
+	if s.names != nil {
+		e, ok := s.names[normalize(name)]
+		return e, ok
+	}
+
+	return Generate(name), true
+}
+
+// Generate returns the entry of the name generated from its hash,
+// the same name always gets the same entry.
+func Generate(name string) Entry {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(normalize(name)))
+	sum := h.Sum32()
+
+	gender := "male"
+	if sum%2 == 1 {
+		gender = "female"
+	}
+
+	first := int(sum % uint32(len(countries)))
+	second := (first + 1 + int(sum>>8)%(len(countries)-1)) % len(countries)
+
+	return Entry{
+		Age:               18 + int(sum>>4)%62,
+		Gender:            gender,
+		GenderProbability: 0.5 + float64(sum>>12%50)/100,
+		Countries: []Country{
+			{ID: countries[first], Probability: 0.6},
+			{ID: countries[second], Probability: 0.2},
+		},
+		Count: 100 + int(sum>>16)%10000,
+	}
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error body in the shape of the APIs.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
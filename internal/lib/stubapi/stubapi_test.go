@@ -0,0 +1,141 @@
+package stubapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, endpoint string, params url.Values) (*http.Response, []byte) {
+	t.Helper()
+
+	resp, err := http.Get(endpoint + "?" + params.Encode())
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("json.Decode() error = %v", err)
+	}
+
+	return resp, body
+}
+
+func TestServer(t *testing.T) {
+	_, baseURL := Start(t, WithNames(map[string]Entry{
+		"Ivan": {Age: 40, Gender: "male", GenderProbability: 0.99, Countries: []Country{{ID: "RU", Probability: 0.5}}, Count: 10},
+	}))
+
+	tests := []struct {
+		name       string
+		path       string
+		params     url.Values
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "agify",
+			path:       PathAgify,
+			params:     url.Values{"name": {"Ivan"}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"count":10,"name":"Ivan","age":40}`,
+		},
+		{
+			name:       "genderize unknown",
+			path:       PathGenderize,
+			params:     url.Values{"name": {"Xyzzy"}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"count":0,"name":"Xyzzy","gender":null,"probability":0}`,
+		},
+		{
+			name:       "nationalize batch",
+			path:       PathNationalize,
+			params:     url.Values{"name[]": {"Ivan", "Xyzzy"}},
+			wantStatus: http.StatusOK,
+			wantBody: `[{"count":10,"name":"Ivan","country":[{"country_id":"RU","probability":0.5}]},` +
+				`{"count":0,"name":"Xyzzy","country":[]}]`,
+		},
+		{
+			name:       "missing name",
+			path:       PathAgify,
+			params:     url.Values{},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"error":"Missing 'name' parameter"}`,
+		},
+		{
+			name:       "too many names",
+			path:       PathAgify,
+			params:     url.Values{"name[]": make([]string, MaxBatchSize+1)},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"error":"Invalid 'name' parameter"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := get(t, baseURL+tt.path, tt.params)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestServer_Limit(t *testing.T) {
+	s, baseURL := Start(t, WithLimit(2, time.Hour))
+
+	resp, _ := get(t, baseURL+PathAgify, url.Values{"name[]": {"Ivan", "Olga"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("X-Rate-Limit-Remaining"); got != "0" {
+		t.Errorf("remaining = %s, want 0", got)
+	}
+	if got := resp.Header.Get("X-Rate-Limit-Reset"); got != "3600" {
+		t.Errorf("reset = %s, want 3600", got)
+	}
+
+	resp, body := get(t, baseURL+PathAgify, url.Values{"name": {"Ivan"}})
+	if resp.StatusCode != http.StatusTooManyRequests || string(body) != `{"error":"Request limit reached"}` {
+		t.Errorf("response = %d %s, want the rate limit error", resp.StatusCode, body)
+	}
+
+	if resp, _ = get(t, baseURL+PathGenderize, url.Values{"name": {"Ivan"}}); resp.StatusCode != http.StatusOK {
+		t.Errorf("the quota of the other API is exhausted")
+	}
+
+	if got := s.Requests(PathAgify); got != 2 {
+		t.Errorf("Server.Requests() = %d, want 2", got)
+	}
+}
+
+func TestServer_Failures(t *testing.T) {
+	_, baseURL := Start(t, WithAPIKey("secret"), WithFailureRate(1))
+
+	resp, body := get(t, baseURL+PathAgify, url.Values{"name": {"Ivan"}})
+	if resp.StatusCode != http.StatusUnauthorized || string(body) != `{"error":"Invalid API key"}` {
+		t.Errorf("response = %d %s, want the api key error", resp.StatusCode, body)
+	}
+
+	resp, _ = get(t, baseURL+PathAgify, url.Values{"name": {"Ivan"}, "apikey": {"secret"}})
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	if Generate("Ivan").Age != Generate(" ivan").Age {
+		t.Errorf("Generate() is not deterministic")
+	}
+
+	e := Generate("Olga")
+	if e.Age < 18 || e.Gender == "" || len(e.Countries) != 2 || e.Countries[0].ID == e.Countries[1].ID {
+		t.Errorf("Generate() = %+v", e)
+	}
+}
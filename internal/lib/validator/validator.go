@@ -1,19 +1,24 @@
 package validator
 
 import (
-	"errors"
+	"reflect"
 	"strings"
+	"sync/atomic"
 
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	ent "github.com/go-playground/validator/v10/translations/en"
+	"github.com/insan1a/exile/internal/lib/personname"
 )
 
 var (
 	validate *validator.Validate
 	uni      *ut.UniversalTranslator
 	trans    ut.Translator
+
+	// nameRules are the rules of the personname tag.
+	nameRules atomic.Pointer[personname.Rules]
 )
 
 func init() {
@@ -22,12 +27,121 @@ func init() {
 	uni = ut.New(en, en)
 	trans, _ = uni.GetTranslator("en")
 	_ = ent.RegisterDefaultTranslations(validate, trans)
+
+	SetNameRules(personname.DefaultRules)
+	_ = validate.RegisterValidation("personname", personName)
+	_ = validate.RegisterTranslation("personname", trans,
+		func(ut ut.Translator) error {
+			return ut.Add("personname", "{0} must contain only letters joined by single {1}", true)
+		},
+		func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T("personname", fe.Field(), punctuation(nameRules.Load().Punctuation))
+			return t
+		},
+	)
+
+	_ = validate.RegisterValidation("sortby", sortBy)
+	_ = validate.RegisterTranslation("sortby", trans,
+		func(ut ut.Translator) error {
+			return ut.Add("sortby", "{0} must be a comma separated list of {1} optionally prefixed with -", true)
+		},
+		func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T("sortby", fe.Field(), strings.Join(strings.Fields(fe.Param()), ", "))
+			return t
+		},
+	)
+}
+
+// SetNameRules sets the letters and the punctuation allowed by the personname tag,
+// personname.DefaultRules are used by default.
+func SetNameRules(r personname.Rules) {
+	nameRules.Store(&r)
+}
+
+// personName validates the name normalized by personname.Normalize by the name rules.
+func personName(fl validator.FieldLevel) bool {
+	return nameRules.Load().Valid(personname.Normalize(fl.Field().String()))
+}
+
+// punctuation returns the readable list of the punctuation characters.
+func punctuation(chars string) string {
+	names := make([]string, 0, len(chars))
+	for _, c := range chars {
+		if c == ' ' {
+			names = append(names, "spaces")
+			continue
+		}
+		names = append(names, string(c))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// sortBy validates the sort order like "surname,-age",
+// the fields are the space separated param of the tag and each of them is used once.
+func sortBy(fl validator.FieldLevel) bool {
+	allowed := make(map[string]bool)
+	for _, f := range strings.Fields(fl.Param()) {
+		allowed[f] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(fl.Field().String(), ",") {
+		f = strings.TrimPrefix(f, "-")
+		if !allowed[f] || seen[f] {
+			return false
+		}
+		seen[f] = true
+	}
+
+	return true
+}
+
+// FieldError is the rule the field failed with its translated message.
+type FieldError struct {
+	// Field is the name the field is sent by.
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError is the failed validation of the struct holding every failed rule.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error returns the messages of the failed rules separated by commas.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Message
+	}
+
+	return strings.Join(msgs, ",")
+}
+
+// Extensions returns the failed rules as the extensions of the GraphQL error.
+func (e *ValidationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   "VALIDATION_FAILED",
+		"errors": e.Errors,
+	}
 }
 
 // ValidateStruct validate struct 'v' using the validator instance and
-// returns an error if the validation fails. The error message includes all the
-// validation errors separated by commas.
+// returns *ValidationError if the validation fails.
+// The fields are named by their json or schema tags as they are sent in the REST requests.
 func ValidateStruct(v any) error {
+	return validateStruct(v, "json", "schema")
+}
+
+// ValidateArgs validates struct 'v' decoded from the GraphQL arguments like ValidateStruct,
+// the fields are named by their mapstructure tags or their lower camel case names.
+func ValidateArgs(v any) error {
+	return validateStruct(v, "mapstructure")
+}
+
+func validateStruct(v any, tags ...string) error {
 	err := validate.Struct(v)
 	if err != nil {
 		errs, ok := err.(validator.ValidationErrors)
@@ -35,12 +149,62 @@ func ValidateStruct(v any) error {
 			return err
 		}
 
-		errMsgs := make([]string, len(errs))
+		verr := &ValidationError{Errors: make([]FieldError, len(errs))}
 		for i, e := range errs {
-			errMsgs[i] = e.Translate(trans)
+			verr.Errors[i] = FieldError{
+				Field:   fieldName(reflect.TypeOf(v), e.StructNamespace(), tags),
+				Rule:    e.Tag(),
+				Message: e.Translate(trans),
+			}
 		}
-		return errors.New(strings.Join(errMsgs, ","))
+		return verr
 	}
 
 	return nil
 }
+
+// fieldName returns the name of the field at the namespace like "Filter.Gender[0]"
+// by the first of the tags set, the fields without the tags are named in lower camel case.
+func fieldName(t reflect.Type, namespace string, tags []string) string {
+	segments := strings.Split(namespace, ".")[1:]
+
+	names := make([]string, len(segments))
+	for i, segment := range segments {
+		name, index, _ := strings.Cut(segment, "[")
+		if index != "" {
+			index = "[" + index
+		}
+
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+
+		names[i] = lowerCamel(name) + index
+		if t.Kind() != reflect.Struct {
+			continue
+		}
+
+		f, ok := t.FieldByName(name)
+		if !ok {
+			continue
+		}
+		t = f.Type
+
+		for _, tag := range tags {
+			if n, _, _ := strings.Cut(f.Tag.Get(tag), ","); n != "" && n != "-" {
+				names[i] = n + index
+				break
+			}
+		}
+	}
+
+	return strings.Join(names, ".")
+}
+
+func lowerCamel(name string) string {
+	if name == "" {
+		return name
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/insan1a/exile/internal/lib/personname"
+)
+
+func TestValidateStruct(t *testing.T) {
+	type filter struct {
+		Sort   string   `schema:"sort" mapstructure:"orderBy" validate:"omitempty,sortby=name age"`
+		AgeMin int      `schema:"age_min" validate:"omitempty,lte=150"`
+		Gender []string `schema:"gender" validate:"omitempty,dive,oneof=male female"`
+	}
+
+	invalid := filter{Sort: "surname", AgeMin: 200, Gender: []string{"male", "x"}}
+
+	tests := []struct {
+		name     string
+		validate func(any) error
+		want     []FieldError
+	}{
+		{
+			name:     "rest",
+			validate: ValidateStruct,
+			want: []FieldError{
+				{Field: "sort", Rule: "sortby", Message: "Sort must be a comma separated list of name, age optionally prefixed with -"},
+				{Field: "age_min", Rule: "lte", Message: "AgeMin must be 150 or less"},
+				{Field: "gender[1]", Rule: "oneof", Message: "Gender[1] must be one of [male female]"},
+			},
+		},
+		{
+			name:     "graphql",
+			validate: ValidateArgs,
+			want: []FieldError{
+				{Field: "orderBy", Rule: "sortby", Message: "Sort must be a comma separated list of name, age optionally prefixed with -"},
+				{Field: "ageMin", Rule: "lte", Message: "AgeMin must be 150 or less"},
+				{Field: "gender[1]", Rule: "oneof", Message: "Gender[1] must be one of [male female]"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verr *ValidationError
+			if err := tt.validate(invalid); !errors.As(err, &verr) {
+				t.Fatalf("validate() error = %v, want *ValidationError", err)
+			}
+			if !reflect.DeepEqual(verr.Errors, tt.want) {
+				t.Errorf("validate() errors = %+v, want %+v", verr.Errors, tt.want)
+			}
+		})
+	}
+
+	if err := ValidateStruct(filter{Sort: "-age,name"}); err != nil {
+		t.Errorf("ValidateStruct() error = %v for the valid struct", err)
+	}
+}
+
+func TestValidateStruct_PersonName(t *testing.T) {
+	type person struct {
+		Surname string `json:"surname" validate:"required,personname"`
+	}
+
+	for _, surname := range []string{"Rimsky-Korsakov", "O'Brien", "Иванов", "  ivanov  "} {
+		if err := ValidateStruct(person{Surname: surname}); err != nil {
+			t.Errorf("ValidateStruct(%q) error = %v", surname, err)
+		}
+	}
+
+	err := ValidateStruct(person{Surname: "Ivanov2"})
+	if want := "Surname must contain only letters joined by single -, ', ’, spaces"; err == nil || err.Error() != want {
+		t.Errorf("ValidateStruct() error = %v, want %q", err, want)
+	}
+
+	rules, _ := personname.NewRules([]string{"Latin"}, "-")
+	SetNameRules(rules)
+	defer SetNameRules(personname.DefaultRules)
+
+	if err = ValidateStruct(person{Surname: "Иванов"}); err == nil {
+		t.Errorf("ValidateStruct() error = nil for the script not allowed by the rules")
+	}
+}
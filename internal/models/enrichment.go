@@ -0,0 +1,51 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+var ErrEnrichmentStatusType = errors.New("the enrichment status must be json")
+
+// EnrichmentStatus holds the errors of the providers failed to enrich the person by their names.
+//
+// The status of the fully enriched person is empty.
+type EnrichmentStatus map[string]string
+
+// Value returns the status as json.
+func (s EnrichmentStatus) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(s)
+}
+
+// Scan reads the status from json.
+func (s *EnrichmentStatus) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return ErrEnrichmentStatusType
+	}
+
+	status := make(EnrichmentStatus)
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+
+	if len(status) == 0 {
+		status = nil
+	}
+	*s = status
+
+	return nil
+}
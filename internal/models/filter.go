@@ -2,74 +2,338 @@ package models
 
 import (
 	"fmt"
+	"strings"
+
 	sq "github.com/Masterminds/squirrel"
 )
 
+// DefaultLimit is the count of the people listed if the limit is not set.
+const DefaultLimit = 10
+
+// The modes of the search by the name, surname and patronymic.
+const (
+	// MatchExact matches the whole value.
+	MatchExact = "exact"
+	// MatchPrefix matches the values starting with the value.
+	MatchPrefix = "prefix"
+	// MatchContains matches the values containing the value.
+	MatchContains = "contains"
+)
+
+// personColumns are the columns of the person in the order of the fields scanned by the storage.
+var personColumns = []string{
+	"id", "name", "surname", "COALESCE(patronymic, '')",
+	"COALESCE(age, 0)", "COALESCE(age_count, 0)",
+	"COALESCE(gender, '')", "COALESCE(gender_probability, 0)", "COALESCE(gender_count, 0)",
+	"COALESCE(nationality, '')", "COALESCE(nationality_probability, 0)", "COALESCE(nationality_count, 0)",
+	"enrichment_status", "enriched_at", "provenance",
+}
+
 type Filter struct {
-	Limit int `schema:"limit" validate:"omitempty,oneof=10 50 100"`
-	Skip  int `schema:"skip" validate:"omitempty,gte=0"`
-
-	Name        string `schema:"name" validate:"omitempty,alpha"`
-	Surname     string `schema:"surname" validate:"omitempty,alpha"`
-	Patronymic  string `schema:"patronymic" validate:"omitempty,alpha"`
-	Age         int    `schema:"age" validate:"omitempty,gte=0,lte=150"`
-	Gender      string `schema:"gender" validate:"omitempty,oneof=male female"`
-	Nationality string `schema:"nationality" validate:"omitempty,len=2"`
+	Limit int `schema:"limit" validate:"omitempty,gte=1,lte=100"`
+	// Skip is the offset of the listing, it is slow on the big tables and is kept for compatibility,
+	// After and Before are preferred.
+	Skip int `schema:"skip" validate:"omitempty,gte=0"`
+	// After and Before are the cursors of the people the page starts after or ends before.
+	After  string `schema:"after" validate:"omitempty,excluded_with=Before Skip"`
+	Before string `schema:"before" validate:"omitempty,excluded_with=After Skip"`
+	// Sort is the order of the people like "surname,-age", the "-" prefix sorts the field descending.
+	// The people of the same values are ordered by ID.
+	Sort string `schema:"sort" mapstructure:"orderBy" validate:"omitempty,sortby=name surname patronymic age gender nationality"`
+	// Include are the aggregations added to the page, IncludeTotal or IncludeFacets,
+	// they do not change the listed people.
+	Include List `schema:"include" validate:"omitempty,dive,oneof=total facets"`
+
+	// Q is the free text matching the name, surname or patronymic by each of its words
+	// ignoring the case.
+	Q string `schema:"q" validate:"omitempty,max=100"`
+	// Match is the mode of the search by the Name, Surname and Patronymic, MatchExact by default.
+	Match      string `schema:"match" validate:"omitempty,oneof=exact prefix contains"`
+	IgnoreCase bool   `schema:"ignore_case"`
+
+	Name       string `schema:"name" validate:"omitempty,personname"`
+	Surname    string `schema:"surname" validate:"omitempty,personname"`
+	Patronymic string `schema:"patronymic" validate:"omitempty,personname"`
+	Age        int    `schema:"age" validate:"omitempty,gte=0,lte=150"`
+	AgeMin     int    `schema:"age_min" validate:"omitempty,gte=0,lte=150"`
+	AgeMax     int    `schema:"age_max" validate:"omitempty,gte=0,lte=150,gtefield=AgeMin"`
+	// Gender and NotGender are the genders the person has or has not,
+	// GenderUnknown matches the people stored without the gender.
+	Gender    List `schema:"gender" validate:"omitempty,dive,oneof=male female unknown"`
+	NotGender List `schema:"gender!" validate:"omitempty,dive,oneof=male female unknown"`
+	// Nationality and NotNationality are the countries any of the nationality candidates is or none of them is.
+	Nationality    List `schema:"nationality" validate:"omitempty,dive,len=2,alpha"`
+	NotNationality List `schema:"nationality!" validate:"omitempty,dive,len=2,alpha"`
+	// NationalityThreshold is the minimal probability of the nationality candidate
+	// matching the Nationality or the NotNationality.
+	NationalityThreshold float64 `schema:"nationality_threshold" validate:"omitempty,gte=0,lte=1"`
+
+	MinAgeCount               int     `schema:"min_age_count" validate:"omitempty,gte=0"`
+	MinGenderProbability      float64 `schema:"min_gender_probability" validate:"omitempty,gte=0,lte=1"`
+	MinNationalityProbability float64 `schema:"min_nationality_probability" validate:"omitempty,gte=0,lte=1"`
 }
 
-func (f Filter) Query() sq.SelectBuilder {
-	builder := sq.StatementBuilder.
-		Select("id", "name", "surname", "patronymic", "age", "gender", "nationality").
-		From("person")
+// Query returns the query of the page of the people in the sort order,
+// one more person than the limit is selected to tell whether there is the next page.
+//
+// If the cursor is malformed or made for the other sort order returns ErrCursor.
+func (f Filter) Query() (sq.SelectBuilder, error) {
+	builder := f.where(sq.StatementBuilder.
+		Select(personColumns...).
+		From("person")).
+		Limit(uint64(f.limit() + 1))
+
+	keys := f.order()
+
+	switch {
+	case f.After != "":
+		c, err := f.decode(f.After)
+		if err != nil {
+			return builder, err
+		}
+		builder = orderBy(builder.Where(seek(keys, c.values(), false)), keys, false)
+	case f.Before != "":
+		c, err := f.decode(f.Before)
+		if err != nil {
+			return builder, err
+		}
+		// the people before the cursor are selected backwards and reversed by the page
+		builder = orderBy(builder.Where(seek(keys, c.values(), true)), keys, true)
+	default:
+		builder = orderBy(builder, keys, false).Offset(uint64(f.Skip))
+	}
+
+	return builder, nil
+}
+
+// CountQuery returns the query of the count of all the people matching the filter.
+func (f Filter) CountQuery() sq.SelectBuilder {
+	return f.where(sq.StatementBuilder.Select("COUNT(*)").From("person"))
+}
+
+// Page returns the page of the people selected by the query.
+//
+// Paging backwards the next page is always reported as the before cursor
+// points at the person following the page, the Relay spec allows not to check
+// whether the person is deleted meanwhile.
+func (f Filter) Page(people []Person) *Page {
+	limit := f.limit()
+	more := len(people) > limit
+	if more {
+		people = people[:limit]
+	}
 
+	page := &Page{People: people}
+	switch {
+	case f.Before != "":
+		for i, j := 0, len(people)-1; i < j; i, j = i+1, j-1 {
+			people[i], people[j] = people[j], people[i]
+		}
+		page.PageInfo.HasPreviousPage = more
+		page.PageInfo.HasNextPage = true
+	default:
+		page.PageInfo.HasNextPage = more
+		page.PageInfo.HasPreviousPage = f.After != "" || f.Skip > 0
+	}
+
+	if len(people) > 0 {
+		page.PageInfo.StartCursor = f.Cursor(people[0]).Encode()
+		page.PageInfo.EndCursor = f.Cursor(people[len(people)-1]).Encode()
+	}
+
+	return page
+}
+
+// Cursor returns the cursor pointing at the person in the sort order of the filter.
+func (f Filter) Cursor(p Person) Cursor {
+	c := Cursor{ID: p.ID, Sort: f.Sort}
+	for _, k := range f.order()[:len(f.order())-1] {
+		c.Values = append(c.Values, k.value(p))
+	}
+
+	return c
+}
+
+// decode returns the cursor of the token made for the sort order of the filter.
+func (f Filter) decode(token string) (Cursor, error) {
+	c, err := DecodeCursor(token)
+	if err != nil {
+		return c, err
+	}
+
+	if c.Sort != f.Sort || len(c.Values) != len(f.order())-1 {
+		return Cursor{}, ErrCursor
+	}
+
+	return c, nil
+}
+
+func (f Filter) limit() int {
 	if f.Limit > 0 {
-		builder = builder.Limit(uint64(f.Limit))
-	} else {
-		builder = builder.Limit(10)
+		return f.Limit
 	}
 
-	if f.Skip > 0 {
-		builder = builder.Offset(uint64(f.Skip))
-	} else {
-		builder = builder.Offset(0)
+	return DefaultLimit
+}
+
+// where adds the conditions of the filter to the query.
+func (f Filter) where(builder sq.SelectBuilder) sq.SelectBuilder {
+	builder = builder.Where(sq.Eq{"is_deleted": false})
+
+	if f.Q != "" {
+		for _, word := range strings.Fields(f.Q) {
+			pattern := "%" + escapeLike(word) + "%"
+			builder = builder.Where(sq.Or{
+				sq.ILike{"name": pattern},
+				sq.ILike{"surname": pattern},
+				sq.ILike{"patronymic": pattern},
+			})
+		}
 	}
 
 	if f.Name != "" {
-		builder = builder.Where(sq.Like{"name": f.Name})
+		builder = builder.Where(f.match("name", f.Name))
 	}
 
 	if f.Surname != "" {
-		builder = builder.Where(sq.Like{"surname": f.Surname})
+		builder = builder.Where(f.match("surname", f.Surname))
 	}
 
 	if f.Patronymic != "" {
-		builder = builder.Where(sq.Like{"patronymic": f.Patronymic})
+		builder = builder.Where(f.match("patronymic", f.Patronymic))
 	}
 
 	if f.Age != 0 {
 		builder = builder.Where(sq.Eq{"age": f.Age})
 	}
 
-	if f.Gender != "" {
-		builder = builder.Where(sq.Eq{"gender": f.Gender})
+	if f.AgeMin > 0 {
+		builder = builder.Where(sq.GtOrEq{"age": f.AgeMin})
+	}
+
+	if f.AgeMax > 0 {
+		builder = builder.Where(sq.LtOrEq{"age": f.AgeMax})
+	}
+
+	if len(f.Gender) > 0 {
+		builder = builder.Where(genderIn(f.Gender))
+	}
+
+	if len(f.NotGender) > 0 {
+		builder = builder.Where(sq.Expr("NOT COALESCE(?, FALSE)", genderIn(f.NotGender)))
+	}
+
+	if len(f.Nationality) > 0 {
+		builder = builder.Where(nationalityIn(f.Nationality, f.NationalityThreshold))
+	}
+
+	if len(f.NotNationality) > 0 {
+		builder = builder.Where(sq.Expr("NOT ?", nationalityIn(f.NotNationality, f.NationalityThreshold)))
+	}
+
+	if f.MinAgeCount > 0 {
+		builder = builder.Where(sq.GtOrEq{"age_count": f.MinAgeCount})
 	}
 
-	if f.Nationality != "" {
-		builder = builder.Where(sq.Eq{"nationality": f.Nationality})
+	if f.MinGenderProbability > 0 {
+		builder = builder.Where(sq.GtOrEq{"gender_probability": f.MinGenderProbability})
+	}
+
+	if f.MinNationalityProbability > 0 {
+		builder = builder.Where(sq.GtOrEq{"nationality_probability": f.MinNationalityProbability})
 	}
 
 	return builder
 }
 
 func (f Filter) String() string {
-	return fmt.Sprintf("filter-limit=%d-skip=%d-name=%s-surname=%s-patronymic=%s-age=%d-gender=%s-nationality=%s",
+	return fmt.Sprintf("filter-limit=%d-skip=%d-after=%s-before=%s-sort=%s-q=%s-match=%s-ignore_case=%t-name=%s-surname=%s-patronymic=%s-age=%d-age_min=%d-age_max=%d"+
+		"-gender=%s-not_gender=%s-nationality=%s-not_nationality=%s-nationality_threshold=%g"+
+		"-min_age_count=%d-min_gender_probability=%g-min_nationality_probability=%g",
 		f.Limit,
 		f.Skip,
+		f.After,
+		f.Before,
+		f.Sort,
+		f.Q,
+		f.Match,
+		f.IgnoreCase,
 		f.Name,
 		f.Surname,
 		f.Patronymic,
 		f.Age,
+		f.AgeMin,
+		f.AgeMax,
 		f.Gender,
+		f.NotGender,
 		f.Nationality,
+		f.NotNationality,
+		f.NationalityThreshold,
+		f.MinAgeCount,
+		f.MinGenderProbability,
+		f.MinNationalityProbability,
+	)
+}
+
+// match returns the condition of the column matching the value in the mode of the filter,
+// the wildcards of the value are matched literally.
+func (f Filter) match(column, value string) sq.Sqlizer {
+	pattern := escapeLike(value)
+	switch f.Match {
+	case MatchPrefix:
+		pattern += "%"
+	case MatchContains:
+		pattern = "%" + pattern + "%"
+	default:
+		if !f.IgnoreCase {
+			return sq.Eq{column: value}
+		}
+	}
+
+	if f.IgnoreCase {
+		return sq.ILike{column: pattern}
+	}
+
+	return sq.Like{column: pattern}
+}
+
+// escapeLike escapes the wildcards of the LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// genderIn returns the condition of the gender in the list,
+// GenderUnknown matches the people without the gender stored as NULL.
+func genderIn(genders List) sq.Or {
+	known := make([]string, 0, len(genders))
+	cond := sq.Or{}
+	for _, g := range genders {
+		if g == GenderUnknown {
+			cond = append(cond, sq.Eq{"gender": nil})
+			continue
+		}
+		known = append(known, g)
+	}
+
+	if len(known) > 0 {
+		cond = append(cond, sq.Eq{"gender": known})
+	}
+
+	return cond
+}
+
+// nationalityIn returns the condition of any of the nationality candidates
+// with the probability above the threshold in the list.
+func nationalityIn(countries List, threshold float64) sq.Sqlizer {
+	args := make([]any, 0, len(countries)+1)
+	for _, c := range countries {
+		args = append(args, c)
+	}
+	args = append(args, threshold)
+
+	return sq.Expr(
+		"EXISTS (SELECT 1 FROM person_nationality pn "+
+			"WHERE pn.person_id = person.id AND pn.country_id IN ("+sq.Placeholders(len(countries))+") AND pn.probability >= ?)",
+		args...,
 	)
 }
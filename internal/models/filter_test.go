@@ -0,0 +1,100 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilter_CountQuery(t *testing.T) {
+	const pn = "EXISTS (SELECT 1 FROM person_nationality pn WHERE pn.person_id = person.id " +
+		"AND pn.country_id IN (?,?,?) AND pn.probability >= ?)"
+
+	tests := []struct {
+		name      string
+		filter    Filter
+		wantWhere string
+		wantArgs  []any
+	}{
+		{
+			name:      "exact name",
+			filter:    Filter{Name: "Ivan"},
+			wantWhere: "is_deleted = ? AND name = ?",
+			wantArgs:  []any{false, "Ivan"},
+		},
+		{
+			name:      "surname prefix ignoring case",
+			filter:    Filter{Surname: "ива", Match: MatchPrefix, IgnoreCase: true},
+			wantWhere: "is_deleted = ? AND surname ILIKE ?",
+			wantArgs:  []any{false, "ива%"},
+		},
+		{
+			name:      "patronymic contains the wildcard",
+			filter:    Filter{Patronymic: "a_b", Match: MatchContains},
+			wantWhere: "is_deleted = ? AND patronymic LIKE ?",
+			wantArgs:  []any{false, `%a\_b%`},
+		},
+		{
+			name:   "free text",
+			filter: Filter{Q: "ivan  petrov"},
+			wantWhere: "is_deleted = ? AND (name ILIKE ? OR surname ILIKE ? OR patronymic ILIKE ?) " +
+				"AND (name ILIKE ? OR surname ILIKE ? OR patronymic ILIKE ?)",
+			wantArgs: []any{false, "%ivan%", "%ivan%", "%ivan%", "%petrov%", "%petrov%", "%petrov%"},
+		},
+		{
+			name:      "age range",
+			filter:    Filter{AgeMin: 18, AgeMax: 30},
+			wantWhere: "is_deleted = ? AND age >= ? AND age <= ?",
+			wantArgs:  []any{false, 18, 30},
+		},
+		{
+			name:      "genders with unknown",
+			filter:    Filter{Gender: List{"female", GenderUnknown}},
+			wantWhere: "is_deleted = ? AND (gender IS NULL OR gender IN (?))",
+			wantArgs:  []any{false, "female"},
+		},
+		{
+			name:      "not gender",
+			filter:    Filter{NotGender: List{"male"}},
+			wantWhere: "is_deleted = ? AND NOT COALESCE((gender IN (?)), FALSE)",
+			wantArgs:  []any{false, "male"},
+		},
+		{
+			name:      "nationalities",
+			filter:    Filter{Nationality: List{"RU", "UA", "KZ"}, NationalityThreshold: 0.1},
+			wantWhere: "is_deleted = ? AND " + pn,
+			wantArgs:  []any{false, "RU", "UA", "KZ", 0.1},
+		},
+		{
+			name:      "not nationality",
+			filter:    Filter{NotNationality: List{"US", "GB", "DE"}},
+			wantWhere: "is_deleted = ? AND NOT " + pn,
+			wantArgs:  []any{false, "US", "GB", "DE", float64(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.filter.CountQuery().ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+
+			if want := "SELECT COUNT(*) FROM person WHERE " + tt.wantWhere; query != want {
+				t.Errorf("Filter.CountQuery() = %s, want %s", query, want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Filter.CountQuery() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestList_UnmarshalText(t *testing.T) {
+	var l List
+	if err := l.UnmarshalText([]byte("RU, UA,,KZ")); err != nil {
+		t.Fatalf("List.UnmarshalText() error = %v", err)
+	}
+
+	if want := (List{"RU", "UA", "KZ"}); !reflect.DeepEqual(l, want) {
+		t.Errorf("List.UnmarshalText() = %v, want %v", l, want)
+	}
+}
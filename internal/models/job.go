@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Job is the progress of the re-enrichment pass.
+type Job struct {
+	Name string `db:"name"`
+	// Cutoff is the time the people enriched before are re-enriched.
+	Cutoff time.Time `db:"cutoff"`
+	// LastID is the ID of the last processed person, the pass continues after it.
+	LastID    string    `db:"last_id"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
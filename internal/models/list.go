@@ -0,0 +1,27 @@
+package models
+
+import "strings"
+
+// GenderUnknown is the gender of the filter matching the people stored without the gender.
+const GenderUnknown = "unknown"
+
+// List is the list of the values of the filter given as "RU,UA,KZ".
+type List []string
+
+// UnmarshalText splits the comma separated values dropping the empty ones.
+func (l *List) UnmarshalText(text []byte) error {
+	list := make(List, 0)
+	for _, v := range strings.Split(string(text), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	*l = list
+
+	return nil
+}
+
+// String returns the comma separated values.
+func (l List) String() string {
+	return strings.Join(l, ",")
+}
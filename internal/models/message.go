@@ -4,3 +4,12 @@ type ErrorMessage struct {
 	Meta  map[string]any `json:"meta"`
 	Error string         `json:"error"`
 }
+
+// ReplyMessage is sent by the person service to the reply topic
+// once the request with the given ID is processed.
+type ReplyMessage struct {
+	RequestID string  `json:"request_id"`
+	Status    string  `json:"status"`
+	Person    *Person `json:"person,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var ErrCursor = errors.New("the cursor is invalid")
+
+// Cursor is the position of the person in the listing,
+// it holds the values of the person in the sort order and the ID breaking the ties.
+type Cursor struct {
+	ID     string `json:"id"`
+	Sort   string `json:"sort,omitempty"`
+	Values []any  `json:"values,omitempty"`
+}
+
+// values returns the values of the cursor ending with the ID.
+func (c Cursor) values() []any {
+	return append(append([]any(nil), c.Values...), c.ID)
+}
+
+// Encode returns the opaque token of the cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor returns the cursor of the token, ErrCursor is returned if the token is malformed.
+func DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrCursor
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, ErrCursor
+	}
+
+	if _, err = uuid.Parse(c.ID); err != nil {
+		return Cursor{}, ErrCursor
+	}
+
+	return c, nil
+}
+
+// PageInfo tells where the page is in the listing.
+type PageInfo struct {
+	// StartCursor and EndCursor are the cursors of the first and the last person of the page.
+	StartCursor     string `json:"start_cursor,omitempty"`
+	EndCursor       string `json:"end_cursor,omitempty"`
+	HasNextPage     bool   `json:"has_next_page"`
+	HasPreviousPage bool   `json:"has_previous_page"`
+}
+
+// Page is the page of the people listed by the filter.
+type Page struct {
+	People   []Person
+	PageInfo PageInfo
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	c := Cursor{ID: "05dd6483-1938-4d8b-9a45-7f61a69ad377"}
+
+	got, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("DecodeCursor() = %v, want %v", got, c)
+	}
+
+	for _, token := range []string{"", "!", Cursor{ID: "1; DROP TABLE person"}.Encode()} {
+		if _, err = DecodeCursor(token); !errors.Is(err, ErrCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, want %v", token, err, ErrCursor)
+		}
+	}
+}
+
+func TestFilter_Page(t *testing.T) {
+	people := []Person{
+		{ID: "05dd6483-1938-4d8b-9a45-7f61a69ad371"},
+		{ID: "05dd6483-1938-4d8b-9a45-7f61a69ad372"},
+		{ID: "05dd6483-1938-4d8b-9a45-7f61a69ad373"},
+	}
+	cursor := Cursor{ID: "05dd6483-1938-4d8b-9a45-7f61a69ad370"}.Encode()
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		people   []Person
+		wantIDs  []string
+		wantInfo PageInfo
+	}{
+		{
+			name:    "first page with more",
+			filter:  Filter{Limit: 2},
+			people:  people,
+			wantIDs: []string{people[0].ID, people[1].ID},
+			wantInfo: PageInfo{
+				StartCursor: Filter{}.Cursor(people[0]).Encode(),
+				EndCursor:   Filter{}.Cursor(people[1]).Encode(),
+				HasNextPage: true,
+			},
+		},
+		{
+			name:    "last page after the cursor",
+			filter:  Filter{Limit: 3, After: cursor},
+			people:  people,
+			wantIDs: []string{people[0].ID, people[1].ID, people[2].ID},
+			wantInfo: PageInfo{
+				StartCursor:     Filter{}.Cursor(people[0]).Encode(),
+				EndCursor:       Filter{}.Cursor(people[2]).Encode(),
+				HasPreviousPage: true,
+			},
+		},
+		{
+			name:    "page before the cursor",
+			filter:  Filter{Limit: 2, Before: cursor},
+			people:  []Person{people[2], people[1], people[0]},
+			wantIDs: []string{people[1].ID, people[2].ID},
+			wantInfo: PageInfo{
+				StartCursor:     Filter{}.Cursor(people[1]).Encode(),
+				EndCursor:       Filter{}.Cursor(people[2]).Encode(),
+				HasNextPage:     true,
+				HasPreviousPage: true,
+			},
+		},
+		{
+			name:   "empty page",
+			filter: Filter{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := tt.filter.Page(append([]Person(nil), tt.people...))
+
+			var ids []string
+			for _, p := range page.People {
+				ids = append(ids, p.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("Filter.Page() people = %v, want %v", ids, tt.wantIDs)
+			}
+			if page.PageInfo != tt.wantInfo {
+				t.Errorf("Filter.Page() info = %+v, want %+v", page.PageInfo, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestFilter_Query(t *testing.T) {
+	id := "05dd6483-1938-4d8b-9a45-7f61a69ad370"
+	sorted := Filter{Sort: "surname,-age"}.Cursor(Person{ID: id, Surname: "Ivanov", Age: 40}).Encode()
+
+	tests := []struct {
+		name       string
+		filter     Filter
+		wantSuffix string
+		wantArgs   []any
+		wantErr    error
+	}{
+		{
+			name:       "after the cursor",
+			filter:     Filter{Limit: 50, After: Filter{}.Cursor(Person{ID: id}).Encode(), Gender: List{"male"}},
+			wantSuffix: "FROM person WHERE is_deleted = ? AND (gender IN (?)) AND ((id > ?)) ORDER BY id ASC LIMIT 51",
+			wantArgs:   []any{false, "male", id},
+		},
+		{
+			name:   "sorted",
+			filter: Filter{Sort: "surname,-age", Skip: 20},
+			wantSuffix: "FROM person WHERE is_deleted = ? " +
+				"ORDER BY surname ASC, COALESCE(age, 0) DESC, id ASC LIMIT 11 OFFSET 20",
+			wantArgs: []any{false},
+		},
+		{
+			name:   "sorted before the cursor",
+			filter: Filter{Sort: "surname,-age", Before: sorted},
+			wantSuffix: "FROM person WHERE is_deleted = ? AND ((surname < ?) " +
+				"OR (surname = ? AND COALESCE(age, 0) > ?) " +
+				"OR (surname = ? AND COALESCE(age, 0) = ? AND id < ?)) " +
+				"ORDER BY surname DESC, COALESCE(age, 0) ASC, id DESC LIMIT 11",
+			wantArgs: []any{false, "Ivanov", "Ivanov", float64(40), "Ivanov", float64(40), id},
+		},
+		{
+			name:    "cursor of the other order",
+			filter:  Filter{Sort: "surname", After: sorted},
+			wantErr: ErrCursor,
+		},
+		{
+			name:    "malformed cursor",
+			filter:  Filter{Before: "broken"},
+			wantErr: ErrCursor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder, err := tt.filter.Query()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Filter.Query() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			query, args, err := builder.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+
+			if !strings.HasSuffix(query, tt.wantSuffix) {
+				t.Errorf("Filter.Query() = %s, want suffix %s", query, tt.wantSuffix)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Filter.Query() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
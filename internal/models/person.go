@@ -1,12 +1,49 @@
 package models
 
+import (
+	"time"
+
+	"github.com/insan1a/exile/internal/lib/personname"
+)
+
 type Person struct {
-	ID          string `db:"id"`
-	Name        string `db:"name" validate:"required,alpha"`
-	Surname     string `db:"surname" validate:"required,alpha"`
-	Patronymic  string `db:"patronymic" validate:"omitempty,alpha"`
-	Age         int    `db:"age"`
-	Gender      string `db:"gender"`
-	Nationality string `db:"nationality"`
-	IsDeleted   bool   `db:"is_deleted"`
+	ID                     string  `db:"id"`
+	Name                   string  `db:"name" validate:"required,personname"`
+	Surname                string  `db:"surname" validate:"required,personname"`
+	Patronymic             string  `db:"patronymic" validate:"omitempty,personname"`
+	Age                    int     `db:"age"`
+	AgeCount               int     `db:"age_count"`
+	Gender                 string  `db:"gender"`
+	GenderProbability      float64 `db:"gender_probability"`
+	GenderCount            int     `db:"gender_count"`
+	Nationality            string  `db:"nationality"`
+	NationalityProbability float64 `db:"nationality_probability"`
+	NationalityCount       int     `db:"nationality_count"`
+	IsDeleted              bool    `db:"is_deleted"`
+
+	// EnrichmentStatus holds the errors of the non-required providers
+	// failed to enrich the person.
+	EnrichmentStatus EnrichmentStatus `db:"enrichment_status"`
+	// EnrichedAt is the time the person was last enriched by the providers.
+	EnrichedAt time.Time `db:"enriched_at"`
+	// Provenance holds the sources of the age, gender and nationality.
+	Provenance Provenance `db:"provenance"`
+
+	// Nationalities are the candidates of the nationality
+	// ordered by the probability descending.
+	Nationalities []Nationality `db:"-"`
+}
+
+// Normalize trims the name, surname and patronymic, collapses their spaces and capitalizes them,
+// the person is normalized before the enrichment and storing.
+func (p *Person) Normalize() {
+	p.Name = personname.Normalize(p.Name)
+	p.Surname = personname.Normalize(p.Surname)
+	p.Patronymic = personname.Normalize(p.Patronymic)
+}
+
+// Nationality is a candidate of the person nationality.
+type Nationality struct {
+	CountryID   string  `db:"country_id"`
+	Probability float64 `db:"probability"`
 }
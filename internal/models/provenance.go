@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrProvenanceType = errors.New("the provenance must be json")
+
+// The sources of the fields besides the names of the providers.
+const (
+	// SourceManual is the source of the fields corrected by a human,
+	// they are never overwritten by the enrichment.
+	SourceManual = "manual"
+	// SourceDataset is the source of the fields found in the local dataset.
+	SourceDataset = "dataset"
+)
+
+// FieldSource is the origin of the value of the field.
+type FieldSource struct {
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Provenance holds the origins of the age, gender and nationality by the field names.
+type Provenance map[string]FieldSource
+
+// Manual reports whether the field is corrected by a human.
+func (p Provenance) Manual(field string) bool {
+	return p[field].Source == SourceManual
+}
+
+// Value returns the provenance as json.
+func (p Provenance) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(p)
+}
+
+// Scan reads the provenance from json.
+func (p *Provenance) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return ErrProvenanceType
+	}
+
+	provenance := make(Provenance)
+	if err := json.Unmarshal(data, &provenance); err != nil {
+		return err
+	}
+
+	if len(provenance) == 0 {
+		provenance = nil
+	}
+	*p = provenance
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+const (
+	RequestStatusPending = "pending"
+	RequestStatusStored  = "stored"
+	RequestStatusFailed  = "failed"
+)
+
+// Request represents the state of the request to save a person.
+type Request struct {
+	ID        string    `db:"id"`
+	Status    string    `db:"status"`
+	PersonID  string    `db:"person_id"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
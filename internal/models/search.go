@@ -0,0 +1,83 @@
+package models
+
+import sq "github.com/Masterminds/squirrel"
+
+const (
+	// DefaultMinScore is the minimal similarity of the found people if it is not set.
+	DefaultMinScore = 0.3
+	// MaxDistance is the Levenshtein distance of the names found regardless of the similarity,
+	// it catches the typos of the short names among the candidates.
+	MaxDistance = 2
+	// MinCandidateScore is the maximal word similarity threshold of the candidates,
+	// the short names with the typos within MaxDistance usually share more trigrams.
+	MinCandidateScore = 0.2
+)
+
+// Search is the typo tolerant search of the people by the name, surname and patronymic,
+// the Latin and Cyrillic spellings are matched by their transliteration.
+type Search struct {
+	Q        string  `schema:"q" validate:"required,min=2,max=100"`
+	Limit    int     `schema:"limit" validate:"omitempty,gte=1,lte=100"`
+	MinScore float64 `schema:"min_score" validate:"omitempty,gte=0,lte=1"`
+}
+
+// SearchResult is the person found with its relevance.
+type SearchResult struct {
+	Person Person `json:"person"`
+	// Score is the trigram similarity of the query to the full name from 0 to 1.
+	Score float64 `json:"score"`
+	// Distance is the Levenshtein distance of the query to the closest of the name, surname and patronymic.
+	Distance int `json:"distance"`
+}
+
+// Threshold returns the word similarity threshold of the candidates selected by the trigram index,
+// it is low enough to catch the typos within MaxDistance of the short names.
+func (s Search) Threshold() float64 {
+	return min(s.minScore(), MinCandidateScore)
+}
+
+func (s Search) minScore() float64 {
+	if s.MinScore == 0 {
+		return DefaultMinScore
+	}
+
+	return s.MinScore
+}
+
+// Query returns the query of the found people with their score and distance
+// ordered by the score and the distance.
+//
+// The candidates are selected by the trigram index with the word similarity operator
+// which depends on the pg_trgm.word_similarity_threshold set to Threshold,
+// the score and the distance are computed only for them.
+func (s Search) Query() sq.SelectBuilder {
+	const (
+		score    = "word_similarity(search.q, search_name)"
+		distance = "LEAST(" +
+			"levenshtein(search.q, person_translit(name)), " +
+			"levenshtein(search.q, person_translit(surname)), " +
+			"levenshtein(search.q, person_translit(COALESCE(patronymic, ''))))"
+	)
+
+	limit := s.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	candidates := sq.StatementBuilder.
+		Select("*").
+		From("person").
+		Where(sq.Eq{"is_deleted": false}).
+		Where(sq.Expr("person_translit(?) <% search_name", s.Q))
+
+	return sq.StatementBuilder.
+		Select(append(append([]string(nil), personColumns...), score+" AS score", distance+" AS distance")...).
+		FromSelect(candidates, "person").
+		JoinClause("CROSS JOIN (SELECT person_translit(?) AS q) AS search", s.Q).
+		Where(sq.Or{
+			sq.Expr(score+" >= ?", s.minScore()),
+			sq.Expr(distance+" <= ?", MaxDistance),
+		}).
+		OrderBy("score DESC", "distance ASC", "id ASC").
+		Limit(uint64(limit))
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearch_Query(t *testing.T) {
+	query, args, err := Search{Q: "Иванов", Limit: 5}.Query().ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"word_similarity(search.q, search_name) AS score",
+		"FROM (SELECT * FROM person WHERE is_deleted = ? AND person_translit(?) <% search_name) AS person " +
+			"CROSS JOIN (SELECT person_translit(?) AS q) AS search",
+		"WHERE (word_similarity(search.q, search_name) >= ? OR LEAST(",
+		"ORDER BY score DESC, distance ASC, id ASC LIMIT 5",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("Search.Query() = %s, want it to contain %s", query, want)
+		}
+	}
+
+	if want := []any{false, "Иванов", "Иванов", DefaultMinScore, MaxDistance}; !reflect.DeepEqual(args, want) {
+		t.Errorf("Search.Query() args = %v, want %v", args, want)
+	}
+}
+
+func TestSearch_Threshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		minScore float64
+		want     float64
+	}{
+		{name: "default", want: MinCandidateScore},
+		{name: "lower min score", minScore: 0.1, want: 0.1},
+		{name: "higher min score", minScore: 0.8, want: MinCandidateScore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Search{Q: "Ivan", MinScore: tt.minScore}).Threshold(); got != tt.want {
+				t.Errorf("Search.Threshold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
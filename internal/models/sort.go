@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// sortColumns are the columns the people can be sorted by,
+// the fields of the sortby tag of the Filter must be the same.
+var sortColumns = map[string]sortColumn{
+	"name":        {expr: "name", value: func(p Person) any { return p.Name }},
+	"surname":     {expr: "surname", value: func(p Person) any { return p.Surname }},
+	"patronymic":  {expr: "COALESCE(patronymic, '')", value: func(p Person) any { return p.Patronymic }},
+	"age":         {expr: "COALESCE(age, 0)", value: func(p Person) any { return p.Age }},
+	"gender":      {expr: "COALESCE(gender, '')", value: func(p Person) any { return p.Gender }},
+	"nationality": {expr: "COALESCE(nationality, '')", value: func(p Person) any { return p.Nationality }},
+}
+
+// idColumn is the tie breaker of any sort order, it makes the order stable.
+var idColumn = sortColumn{expr: "id", value: func(p Person) any { return p.ID }}
+
+// sortColumn is the expression the people are ordered by with its value of the person.
+type sortColumn struct {
+	expr  string
+	value func(p Person) any
+}
+
+// sortKey is the column of the sort order with its direction.
+type sortKey struct {
+	sortColumn
+	desc bool
+}
+
+// order returns the keys of the sort order of the filter ending with the ID.
+func (f Filter) order() []sortKey {
+	var keys []sortKey
+	if f.Sort != "" {
+		for _, field := range strings.Split(f.Sort, ",") {
+			name := strings.TrimPrefix(field, "-")
+			keys = append(keys, sortKey{sortColumn: sortColumns[name], desc: name != field})
+		}
+	}
+
+	return append(keys, sortKey{sortColumn: idColumn})
+}
+
+// orderBy orders the query by the keys, the backward order reverses the directions.
+func orderBy(builder sq.SelectBuilder, keys []sortKey, backward bool) sq.SelectBuilder {
+	for _, k := range keys {
+		dir := "ASC"
+		if k.desc != backward {
+			dir = "DESC"
+		}
+		builder = builder.OrderBy(k.expr + " " + dir)
+	}
+
+	return builder
+}
+
+// seek returns the condition of the people after the values of the keys in the order,
+// the backward condition matches the people before them.
+func seek(keys []sortKey, values []any, backward bool) sq.Or {
+	cond := make(sq.Or, 0, len(keys))
+	for i, k := range keys {
+		op := ">"
+		if k.desc != backward {
+			op = "<"
+		}
+
+		and := make(sq.And, 0, i+1)
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Expr(keys[j].expr+" = ?", values[j]))
+		}
+		and = append(and, sq.Expr(fmt.Sprintf("%s %s ?", k.expr, op), values[i]))
+
+		cond = append(cond, and)
+	}
+
+	return cond
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const (
+	// PeopleGeneration is the key of the generation of the cached pages and statistics
+	// of the people, it is bumped on every write of the people.
+	PeopleGeneration = "people-generation"
+	// DefaultAgeBucket is the width of the age histogram bucket if it is not set.
+	DefaultAgeBucket = 10
+	// DefaultInterval is the period the people are counted by over time if it is not set.
+	DefaultInterval = "month"
+)
+
+// Statistics is the request of the demographics of the stored people.
+type Statistics struct {
+	AgeBucket int    `schema:"age_bucket" validate:"omitempty,gte=1,lte=150"`
+	Interval  string `schema:"interval" validate:"omitempty,oneof=day week month year"`
+}
+
+// AgeBucket is the count of the people of the age from From to To inclusive.
+type AgeBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// NationalityStatistics is the age and the gender ratio of the people of the nationality.
+type NationalityStatistics struct {
+	Nationality string  `json:"nationality"`
+	Count       int     `json:"count"`
+	MeanAge     float64 `json:"mean_age"`
+	MedianAge   float64 `json:"median_age"`
+	// MaleRatio is the share of the men among the people of the known gender from 0 to 1.
+	MaleRatio float64 `json:"male_ratio"`
+}
+
+// PeriodCount is the count of the people created in the period and the total count by its end.
+type PeriodCount struct {
+	Period time.Time `json:"period"`
+	Count  int       `json:"count"`
+	Total  int       `json:"total"`
+}
+
+// StatisticsResult is the demographics of the stored people.
+type StatisticsResult struct {
+	AgeHistogram  []AgeBucket             `json:"age_histogram"`
+	Nationalities []NationalityStatistics `json:"nationalities"`
+	Timeline      []PeriodCount           `json:"timeline"`
+}
+
+// AgeQuery returns the query of the age histogram ordered by the age,
+// each row is the lowest age of the bucket and the count.
+func (s Statistics) AgeQuery() sq.SelectBuilder {
+	return sq.StatementBuilder.
+		Select().
+		Column(sq.Expr("(age / ?) * ? AS bucket", s.ageBucket(), s.ageBucket())).
+		Column("COUNT(*)").
+		From("person").
+		Where(sq.Eq{"is_deleted": false}).
+		Where(sq.NotEq{"age": nil}).
+		GroupBy("bucket").
+		OrderBy("bucket")
+}
+
+// NationalityQuery returns the query of the count, the mean and median age and the male ratio
+// of each nationality ordered by the count descending.
+func (s Statistics) NationalityQuery() sq.SelectBuilder {
+	return sq.StatementBuilder.
+		Select(
+			"nationality",
+			"COUNT(*)",
+			"COALESCE(AVG(age), 0)",
+			"COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY age), 0)",
+			"COALESCE(COUNT(*) FILTER (WHERE gender = 'male')::float8 / "+
+				"NULLIF(COUNT(*) FILTER (WHERE gender IN ('male', 'female')), 0), 0)",
+		).
+		From("person").
+		Where(sq.Eq{"is_deleted": false}).
+		Where(sq.NotEq{"nationality": nil}).
+		GroupBy("nationality").
+		OrderBy("2 DESC", "nationality")
+}
+
+// TimelineQuery returns the query of the count of the people created in each period
+// and the running total ordered by the period.
+func (s Statistics) TimelineQuery() sq.SelectBuilder {
+	periods := sq.Select().
+		Column(sq.Expr("date_trunc(?, created_at) AS period", s.interval())).
+		Column("COUNT(*) AS count").
+		From("person").
+		Where(sq.Eq{"is_deleted": false}).
+		GroupBy("period")
+
+	return sq.StatementBuilder.
+		Select("period", "count", "SUM(count) OVER (ORDER BY period)").
+		FromSelect(periods, "periods").
+		OrderBy("period")
+}
+
+// Bucket returns the bucket of the histogram starting from the age.
+func (s Statistics) Bucket(from, count int) AgeBucket {
+	return AgeBucket{From: from, To: from + s.ageBucket() - 1, Count: count}
+}
+
+func (s Statistics) String() string {
+	return fmt.Sprintf("statistics-age_bucket=%d-interval=%s", s.ageBucket(), s.interval())
+}
+
+func (s Statistics) ageBucket() int {
+	if s.AgeBucket > 0 {
+		return s.AgeBucket
+	}
+
+	return DefaultAgeBucket
+}
+
+func (s Statistics) interval() string {
+	if s.Interval != "" {
+		return s.Interval
+	}
+
+	return DefaultInterval
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func TestStatistics_Queries(t *testing.T) {
+	s := Statistics{AgeBucket: 5, Interval: "week"}
+
+	tests := []struct {
+		name     string
+		query    sq.SelectBuilder
+		want     string
+		wantArgs []any
+	}{
+		{
+			name:  "age histogram",
+			query: s.AgeQuery(),
+			want: "SELECT (age / ?) * ? AS bucket, COUNT(*) FROM person " +
+				"WHERE is_deleted = ? AND age IS NOT NULL GROUP BY bucket ORDER BY bucket",
+			wantArgs: []any{5, 5, false},
+		},
+		{
+			name:  "timeline",
+			query: s.TimelineQuery(),
+			want: "SELECT period, count, SUM(count) OVER (ORDER BY period) FROM " +
+				"(SELECT date_trunc(?, created_at) AS period, COUNT(*) AS count FROM person " +
+				"WHERE is_deleted = ? GROUP BY period) AS periods ORDER BY period",
+			wantArgs: []any{"week", false},
+		},
+		{
+			name:     "default timeline interval",
+			query:    Statistics{}.TimelineQuery(),
+			wantArgs: []any{DefaultInterval, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := tt.query.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("ToSql() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("ToSql() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestStatistics_Bucket(t *testing.T) {
+	if got, want := (Statistics{}).Bucket(20, 3), (AgeBucket{From: 20, To: 29, Count: 3}); got != want {
+		t.Errorf("Statistics.Bucket() = %v, want %v", got, want)
+	}
+}
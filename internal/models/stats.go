@@ -0,0 +1,102 @@
+package models
+
+import sq "github.com/Masterminds/squirrel"
+
+// The values of the listing aggregations besides the genders and the countries.
+const (
+	// FacetUnknown is the value of the people stored without the field.
+	FacetUnknown = "unknown"
+)
+
+// The aggregations of the listing added to the page by the Include of the filter.
+const (
+	IncludeTotal  = "total"
+	IncludeFacets = "facets"
+)
+
+// ageBucket is the expression of the age range of the person.
+const ageBucket = `CASE
+	WHEN age IS NULL THEN 'unknown'
+	WHEN age < 18 THEN '0-17'
+	WHEN age < 25 THEN '18-24'
+	WHEN age < 35 THEN '25-34'
+	WHEN age < 45 THEN '35-44'
+	WHEN age < 55 THEN '45-54'
+	WHEN age < 65 THEN '55-64'
+	ELSE '65+'
+END`
+
+// The values of GROUPING(gender, nationality, age) of the grouping sets of the stats query.
+const (
+	GroupingTotal       = 7
+	GroupingGender      = 3
+	GroupingNationality = 5
+	GroupingAge         = 6
+)
+
+// Facet is the count of the people with the value of the field.
+type Facet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets are the counts of the people by the gender, nationality and age range.
+type Facets struct {
+	Gender      []Facet `json:"gender"`
+	Nationality []Facet `json:"nationality"`
+	Age         []Facet `json:"age"`
+}
+
+// Stats are the total count and the facets of the people matching the filter.
+type Stats struct {
+	Total  int    `json:"total"`
+	Facets Facets `json:"facets"`
+}
+
+// Add adds the count of the grouping set of the stats query to the stats.
+func (s *Stats) Add(grouping int, value string, count int) {
+	switch grouping {
+	case GroupingTotal:
+		s.Total = count
+	case GroupingGender:
+		s.Facets.Gender = append(s.Facets.Gender, Facet{Value: value, Count: count})
+	case GroupingNationality:
+		s.Facets.Nationality = append(s.Facets.Nationality, Facet{Value: value, Count: count})
+	case GroupingAge:
+		s.Facets.Age = append(s.Facets.Age, Facet{Value: value, Count: count})
+	}
+}
+
+// StatsQuery returns the query of the total count and the counts of the people matching the filter
+// by the gender, nationality and age range in one round trip.
+//
+// Each row is the grouping set with GROUPING(gender, nationality, age),
+// the value of the grouped field and the count ordered by the count descending.
+func (f Filter) StatsQuery() sq.SelectBuilder {
+	people := f.where(sq.Select(
+		"COALESCE(gender, 'unknown') AS gender",
+		"COALESCE(nationality, 'unknown') AS nationality",
+		ageBucket+" AS age",
+	).From("person"))
+
+	return sq.StatementBuilder.
+		Select(
+			"GROUPING(gender, nationality, age)",
+			"COALESCE(gender, nationality, age, '')",
+			"COUNT(*)",
+		).
+		FromSelect(people, "people").
+		GroupBy("GROUPING SETS ((), (gender), (nationality), (age))").
+		OrderBy("1", "3 DESC", "2")
+}
+
+// Includes reports whether the aggregation is added to the page.
+func (f Filter) Includes(aggregation string) bool {
+	for _, a := range f.Include {
+		if a == aggregation {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilter_StatsQuery(t *testing.T) {
+	query, args, err := Filter{Gender: List{"male"}, Limit: 5, Sort: "age"}.StatsQuery().ToSql()
+	if err != nil {
+		t.Fatalf("Filter.StatsQuery() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"SELECT GROUPING(gender, nationality, age), COALESCE(gender, nationality, age, ''), COUNT(*) FROM (",
+		"FROM person WHERE is_deleted = ? AND (gender IN (?))) AS people",
+		"GROUP BY GROUPING SETS ((), (gender), (nationality), (age)) ORDER BY 1, 3 DESC, 2",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("Filter.StatsQuery() = %q, want it to contain %q", query, want)
+		}
+	}
+	if strings.Contains(query, "LIMIT") {
+		t.Errorf("Filter.StatsQuery() = %q is limited by the page", query)
+	}
+	if want := []any{false, "male"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("Filter.StatsQuery() args = %v, want %v", args, want)
+	}
+}
+
+func TestStats_Add(t *testing.T) {
+	var got Stats
+	got.Add(GroupingTotal, "", 3)
+	got.Add(GroupingGender, "male", 2)
+	got.Add(GroupingGender, FacetUnknown, 1)
+	got.Add(GroupingNationality, "RU", 3)
+	got.Add(GroupingAge, "18-24", 3)
+
+	want := Stats{Total: 3, Facets: Facets{
+		Gender:      []Facet{{Value: "male", Count: 2}, {Value: FacetUnknown, Count: 1}},
+		Nationality: []Facet{{Value: "RU", Count: 3}},
+		Age:         []Facet{{Value: "18-24", Count: 3}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats.Add() = %+v, want %+v", got, want)
+	}
+}
@@ -1,8 +1,21 @@
 package response
 
+import (
+	"errors"
+
+	"github.com/insan1a/exile/internal/lib/validator"
+)
+
 type Response struct {
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+	Status string       `json:"status"`
+	Error  string       `json:"error,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is the invalid field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 const (
@@ -20,3 +33,19 @@ func Error(err string) Response {
 		Error:  err,
 	}
 }
+
+// ValidationError returns the error response listing the invalid fields
+// if err is *validator.ValidationError, otherwise the response with the message of err.
+func ValidationError(err error) Response {
+	res := Error(err.Error())
+
+	var verr *validator.ValidationError
+	if errors.As(err, &verr) {
+		res.Errors = make([]FieldError, len(verr.Errors))
+		for i, fe := range verr.Errors {
+			res.Errors[i] = FieldError{Field: fe.Field, Message: fe.Message}
+		}
+	}
+
+	return res
+}
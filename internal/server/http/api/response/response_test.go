@@ -1,8 +1,11 @@
 package response
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/insan1a/exile/internal/lib/validator"
 )
 
 func TestOK(t *testing.T) {
@@ -40,3 +43,39 @@ func TestError(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Response
+	}{
+		{
+			name: "validation error",
+			err: &validator.ValidationError{Errors: []validator.FieldError{
+				{Field: "name", Rule: "required", Message: "Name is a required field"},
+				{Field: "age", Rule: "lte", Message: "Age must be 150 or less"},
+			}},
+			want: Response{
+				Status: StatusError,
+				Error:  "Name is a required field,Age must be 150 or less",
+				Errors: []FieldError{
+					{Field: "name", Message: "Name is a required field"},
+					{Field: "age", Message: "Age must be 150 or less"},
+				},
+			},
+		},
+		{
+			name: "other error",
+			err:  errors.New("invalid request"),
+			want: Response{Status: StatusError, Error: "invalid request"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidationError(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidationError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
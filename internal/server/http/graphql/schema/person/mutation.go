@@ -2,6 +2,8 @@ package person
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/graphql-go/graphql"
@@ -12,14 +14,34 @@ import (
 	"github.com/insan1a/exile/internal/server/http/handlers/person/delete"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/save"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/update"
+	"github.com/insan1a/exile/internal/service"
 	"github.com/mitchellh/mapstructure"
 )
 
+// ProcessingError is returned when the person is not stored before the reply timeout,
+// the person is looked up by the request ID later.
+type ProcessingError struct {
+	RequestID string
+}
+
+// Error returns the message with the request ID.
+func (e *ProcessingError) Error() string {
+	return fmt.Sprintf("the person is still processing, request id: %s", e.RequestID)
+}
+
+// Extensions returns the request ID as the extensions of the GraphQL error.
+func (e *ProcessingError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":      "PROCESSING",
+		"requestId": e.RequestID,
+	}
+}
+
 func Save(log *slog.Logger, saver save.PersonSaver) func(params graphql.ResolveParams) (interface{}, error) {
 	type req struct {
-		Name       string `mapstructure:"name" validate:"required,alpha"`
-		Surname    string `mapstructure:"surname" validate:"required,alpha"`
-		Patronymic string `mapstructure:"patronymic" validate:"omitempty,alpha"`
+		Name       string `mapstructure:"name" validate:"required,personname"`
+		Surname    string `mapstructure:"surname" validate:"required,personname"`
+		Patronymic string `mapstructure:"patronymic" validate:"omitempty,personname"`
 	}
 	return func(params graphql.ResolveParams) (interface{}, error) {
 		var input req
@@ -31,7 +53,7 @@ func Save(log *slog.Logger, saver save.PersonSaver) func(params graphql.ResolveP
 			return nil, err
 		}
 
-		if err := validator.ValidateStruct(input); err != nil {
+		if err := validator.ValidateArgs(input); err != nil {
 			msg := "failed to validate request params"
 
 			log.Error(msg, sl.Err(err), slog.Any("input", input))
@@ -39,12 +61,18 @@ func Save(log *slog.Logger, saver save.PersonSaver) func(params graphql.ResolveP
 			return nil, err
 		}
 
-		p := models.Person{
+		id, p, err := saver.SaveAndWait(context.Background(), models.Person{
 			Name:       input.Name,
 			Surname:    input.Surname,
 			Patronymic: input.Patronymic,
-		}
-		if err := saver.Save(context.Background(), p); err != nil {
+		})
+		if err != nil {
+			if errors.Is(err, service.ErrReplyTimeout) || errors.Is(err, service.ErrRepliesDisabled) {
+				log.Info("the person is still processing", sl.Err(err), slog.String("person_request_id", id))
+
+				return nil, &ProcessingError{RequestID: id}
+			}
+
 			msg := "failed to save person"
 
 			log.Error(msg, sl.Err(err), slog.Any("input", input))
@@ -61,9 +89,9 @@ func Save(log *slog.Logger, saver save.PersonSaver) func(params graphql.ResolveP
 func Update(log *slog.Logger, updater update.PersonUpdater) func(params graphql.ResolveParams) (interface{}, error) {
 	type req struct {
 		ID          string `mapstructure:"id" validate:"required,uuid"`
-		Name        string `mapstructure:"name" validate:"omitempty,alpha"`
-		Surname     string `mapstructure:"surname" validate:"omitempty,alpha"`
-		Patronymic  string `mapstructure:"patronymic" validate:"omitempty,alpha"`
+		Name        string `mapstructure:"name" validate:"omitempty,personname"`
+		Surname     string `mapstructure:"surname" validate:"omitempty,personname"`
+		Patronymic  string `mapstructure:"patronymic" validate:"omitempty,personname"`
 		Age         int    `mapstructure:"age" validate:"omitempty,min=0"`
 		Gender      string `mapstructure:"gender" validate:"omitempty,alpha"`
 		Nationality string `mapstructure:"nationality" validate:"omitempty,alpha"`
@@ -76,7 +104,7 @@ func Update(log *slog.Logger, updater update.PersonUpdater) func(params graphql.
 			log.Error(msg, sl.Err(err), slog.Any("args", params.Args))
 		}
 
-		if err := validator.ValidateStruct(input); err != nil {
+		if err := validator.ValidateArgs(input); err != nil {
 			msg := "failed to validate request params"
 
 			log.Error(msg, sl.Err(err), slog.Any("input", input))
@@ -122,7 +150,7 @@ func Delete(log *slog.Logger, deleter delete.PersonDeleter) func(params graphql.
 			return nil, err
 		}
 
-		if err := validator.ValidateStruct(input); err != nil {
+		if err := validator.ValidateArgs(input); err != nil {
 			msg := "failed to validate request params"
 
 			log.Error(msg, sl.Err(err), slog.Any("input", input))
@@ -0,0 +1,35 @@
+package person
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/save/mocks"
+	"github.com/insan1a/exile/internal/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSave_Processing(t *testing.T) {
+	saver := mocks.NewPersonSaver(t)
+	saver.On("SaveAndWait", mock.Anything, mock.Anything).Once().Return("42", nil, service.ErrReplyTimeout)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	_, err := Save(log, saver)(graphql.ResolveParams{Args: map[string]interface{}{
+		"name":    "Dmitriy",
+		"surname": "Ushakov",
+	}})
+
+	var perr *ProcessingError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Save() error = %v, want *ProcessingError", err)
+	}
+
+	want := map[string]interface{}{"code": "PROCESSING", "requestId": "42"}
+	if got := perr.Extensions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProcessingError.Extensions() = %v, want %v", got, want)
+	}
+}
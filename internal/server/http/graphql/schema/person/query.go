@@ -2,7 +2,10 @@ package person
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sort"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/insan1a/exile/internal/lib/sl"
@@ -10,9 +13,14 @@ import (
 	"github.com/insan1a/exile/internal/models"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/get"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/list"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/request"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/search"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/stats"
 	"github.com/mitchellh/mapstructure"
 )
 
+var ErrFirstAndLast = errors.New("first and last can not be used together")
+
 func List(log *slog.Logger, listter list.PersonLister) func(graphql.ResolveParams) (interface{}, error) {
 	return func(params graphql.ResolveParams) (interface{}, error) {
 		var input models.Filter
@@ -24,7 +32,7 @@ func List(log *slog.Logger, listter list.PersonLister) func(graphql.ResolveParam
 			return nil, err
 		}
 
-		if err := validator.ValidateStruct(input); err != nil {
+		if err := validator.ValidateArgs(input); err != nil {
 			msg := "failed to validate request"
 
 			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
@@ -43,10 +51,205 @@ func List(log *slog.Logger, listter list.PersonLister) func(graphql.ResolveParam
 
 		log.Info("people found", slog.Any("input", input), slog.Any("args", params.Args))
 
-		return p, nil
+		return p.People, nil
+	}
+}
+
+func Search(log *slog.Logger, searcher search.PersonSearcher) func(graphql.ResolveParams) (interface{}, error) {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		var input models.Search
+		if err := mapstructure.Decode(params.Args, &input); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		if err := validator.ValidateArgs(input); err != nil {
+			msg := "failed to validate request"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		results, err := searcher.Search(context.Background(), &input)
+		if err != nil {
+			msg := "failed to search people"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		log.Info("people found", slog.Any("input", input), slog.Int("count", len(results)))
+
+		return results, nil
 	}
 }
 
+// Stats returns the total count and the facets of the people matching the filter.
+func Stats(log *slog.Logger, aggregator list.PersonAggregator) func(graphql.ResolveParams) (interface{}, error) {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		var input models.Filter
+		if err := mapstructure.Decode(params.Args, &input); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		if err := validator.ValidateArgs(input); err != nil {
+			msg := "failed to validate request"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		stats, err := aggregator.Stats(context.Background(), &input)
+		if err != nil {
+			msg := "failed to get people stats"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		log.Info("people counted", slog.Any("input", input), slog.Int("total", stats.Total))
+
+		return stats, nil
+	}
+}
+
+// Statistics returns the demographics of the people.
+func Statistics(log *slog.Logger, getter stats.StatisticsGetter) func(graphql.ResolveParams) (interface{}, error) {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		var input models.Statistics
+		if err := mapstructure.Decode(params.Args, &input); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		if err := validator.ValidateArgs(input); err != nil {
+			msg := "failed to validate request"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		result, err := getter.Statistics(context.Background(), &input)
+		if err != nil {
+			msg := "failed to get statistics"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		log.Info("statistics found", slog.Any("input", input))
+
+		return result, nil
+	}
+}
+
+// peopleConnection is the page of the people in the shape of the Relay connection,
+// the filter is kept to count all the people only if the total count is requested.
+type peopleConnection struct {
+	page    *models.Page
+	filter  models.Filter
+	counter list.PersonCounter
+}
+
+// personEdge is the person with its cursor.
+type personEdge struct {
+	Cursor string
+	Node   models.Person
+}
+
+// Connection returns the page of the people selected by first and after or by last and before.
+func Connection(log *slog.Logger, svc PeopleServicer) func(graphql.ResolveParams) (interface{}, error) {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		_, first := params.Args["first"]
+		_, last := params.Args["last"]
+		if first && last {
+			msg := "failed to validate request"
+
+			log.Error(msg, sl.Err(ErrFirstAndLast), slog.Any("args", params.Args))
+
+			return nil, ErrFirstAndLast
+		}
+
+		// first and last are the limit of the page after and before the cursor
+		args := make(map[string]interface{}, len(params.Args))
+		for k, v := range params.Args {
+			switch k {
+			case "first", "last":
+				args["limit"] = v
+			default:
+				args[k] = v
+			}
+		}
+
+		var input models.Filter
+		if err := mapstructure.Decode(args, &input); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		if err := validator.ValidateArgs(input); err != nil {
+			msg := "failed to validate request"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		p, err := svc.List(context.Background(), &input, input.String())
+		if err != nil {
+			msg := "failed to find people"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		log.Info("people found", slog.Any("input", input), slog.Any("args", params.Args))
+
+		return peopleConnection{page: p, filter: input, counter: svc}, nil
+	}
+}
+
+func resolveEdges(p graphql.ResolveParams) (interface{}, error) {
+	conn := p.Source.(peopleConnection)
+
+	edges := make([]personEdge, len(conn.page.People))
+	for i, person := range conn.page.People {
+		edges[i] = personEdge{Cursor: conn.filter.Cursor(person).Encode(), Node: person}
+	}
+
+	return edges, nil
+}
+
+func resolvePageInfo(p graphql.ResolveParams) (interface{}, error) {
+	return p.Source.(peopleConnection).page.PageInfo, nil
+}
+
+func resolveTotalCount(p graphql.ResolveParams) (interface{}, error) {
+	conn := p.Source.(peopleConnection)
+
+	return conn.counter.Count(context.Background(), &conn.filter)
+}
+
 func One(log *slog.Logger, getter get.PersonGetter) func(graphql.ResolveParams) (interface{}, error) {
 	type req struct {
 		ID string `mapstructure:"id" validate:"required,uuid"`
@@ -61,7 +264,7 @@ func One(log *slog.Logger, getter get.PersonGetter) func(graphql.ResolveParams)
 			return nil, err
 		}
 
-		if err := validator.ValidateStruct(input); err != nil {
+		if err := validator.ValidateArgs(input); err != nil {
 			msg := "failed to validate request"
 
 			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
@@ -83,3 +286,95 @@ func One(log *slog.Logger, getter get.PersonGetter) func(graphql.ResolveParams)
 		return p, nil
 	}
 }
+
+func Request(log *slog.Logger, getter request.RequestGetter) func(graphql.ResolveParams) (interface{}, error) {
+	type req struct {
+		ID string `mapstructure:"id" validate:"required,uuid"`
+	}
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		var input req
+		if err := mapstructure.Decode(params.Args, &input); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		if err := validator.ValidateArgs(input); err != nil {
+			msg := "failed to validate request"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		r, err := getter.GetRequest(context.Background(), input.ID)
+		if err != nil {
+			msg := "failed to get request"
+
+			log.Error(msg, sl.Err(err), slog.Any("input", input), slog.Any("args", params.Args))
+
+			return nil, err
+		}
+
+		log.Info("request found", slog.Any("input", input), slog.Any("request", r))
+
+		return r, nil
+	}
+}
+
+// providerFailure is the failure of the provider in the enrichment status.
+type providerFailure struct {
+	Provider string
+	Error    string
+}
+
+// resolveEnrichmentStatus returns the failures of the providers ordered by their names.
+func resolveEnrichmentStatus(p graphql.ResolveParams) (interface{}, error) {
+	var status models.EnrichmentStatus
+	switch person := p.Source.(type) {
+	case models.Person:
+		status = person.EnrichmentStatus
+	case *models.Person:
+		status = person.EnrichmentStatus
+	}
+
+	failures := make([]providerFailure, 0, len(status))
+	for provider, reason := range status {
+		failures = append(failures, providerFailure{Provider: provider, Error: reason})
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Provider < failures[j].Provider
+	})
+
+	return failures, nil
+}
+
+// fieldSource is the source of the field in the provenance.
+type fieldSource struct {
+	Field     string
+	Source    string
+	UpdatedAt time.Time
+}
+
+// resolveProvenance returns the sources of the fields ordered by the field names.
+func resolveProvenance(p graphql.ResolveParams) (interface{}, error) {
+	var provenance models.Provenance
+	switch person := p.Source.(type) {
+	case models.Person:
+		provenance = person.Provenance
+	case *models.Person:
+		provenance = person.Provenance
+	}
+
+	sources := make([]fieldSource, 0, len(provenance))
+	for field, s := range provenance {
+		sources = append(sources, fieldSource{Field: field, Source: s.Source, UpdatedAt: s.UpdatedAt})
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Field < sources[j].Field
+	})
+
+	return sources, nil
+}
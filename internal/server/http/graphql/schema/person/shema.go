@@ -7,7 +7,10 @@ import (
 	"github.com/insan1a/exile/internal/server/http/handlers/person/delete"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/get"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/list"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/request"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/save"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/search"
+	"github.com/insan1a/exile/internal/server/http/handlers/person/stats"
 	"github.com/insan1a/exile/internal/server/http/handlers/person/update"
 )
 
@@ -16,11 +19,55 @@ type PeopleServicer interface {
 	get.PersonGetter
 	save.PersonSaver
 	list.PersonLister
+	list.PersonAggregator
+	search.PersonSearcher
+	stats.StatisticsGetter
 	delete.PersonDeleter
 	update.PersonUpdater
+	request.RequestGetter
 }
 
 func New(log *slog.Logger, svc PeopleServicer) (graphql.Schema, error) {
+	nationalityType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Nationality",
+		Fields: graphql.Fields{
+			"countryId": &graphql.Field{
+				Type: graphql.String,
+			},
+			"probability": &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	})
+
+	providerFailureType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProviderFailure",
+		Fields: graphql.Fields{
+			"provider": &graphql.Field{
+				Type: graphql.String,
+			},
+			"error": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+
+	fieldSourceType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "FieldSource",
+		Fields: graphql.Fields{
+			"field": &graphql.Field{
+				Type: graphql.String,
+			},
+			"source": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Provider, manual or dataset",
+			},
+			"updatedAt": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	})
+
 	personType := graphql.NewObject(graphql.ObjectConfig{
 		Name: "Person",
 		Fields: graphql.Fields{
@@ -39,12 +86,256 @@ func New(log *slog.Logger, svc PeopleServicer) (graphql.Schema, error) {
 			"age": &graphql.Field{
 				Type: graphql.Int,
 			},
+			"ageCount": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Count of samples the age is guessed from",
+			},
 			"gender": &graphql.Field{
 				Type: graphql.String,
 			},
+			"genderProbability": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Probability of the gender",
+			},
+			"genderCount": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Count of samples the gender is guessed from",
+			},
 			"nationality": &graphql.Field{
 				Type: graphql.String,
 			},
+			"nationalityProbability": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Probability of the nationality",
+			},
+			"nationalityCount": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Count of samples the nationality is guessed from",
+			},
+			"nationalities": &graphql.Field{
+				Type:        graphql.NewList(nationalityType),
+				Description: "Candidates of the nationality ordered by the probability",
+			},
+			"enrichmentStatus": &graphql.Field{
+				Type:        graphql.NewList(providerFailureType),
+				Description: "Failures of the providers the person is stored without",
+				Resolve:     resolveEnrichmentStatus,
+			},
+			"enrichedAt": &graphql.Field{
+				Type:        graphql.DateTime,
+				Description: "Time of the last enrichment by the providers",
+			},
+			"provenance": &graphql.Field{
+				Type:        graphql.NewList(fieldSourceType),
+				Description: "Sources of the age, gender and nationality, the manual ones are never re-enriched",
+				Resolve:     resolveProvenance,
+			},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+			},
+			"hasPreviousPage": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+			},
+			"startCursor": &graphql.Field{
+				Type: graphql.String,
+			},
+			"endCursor": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+
+	personEdgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PersonEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"node": &graphql.Field{
+				Type: personType,
+			},
+		},
+	})
+
+	peopleConnectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PeopleConnection",
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{
+				Type:    graphql.NewList(personEdgeType),
+				Resolve: resolveEdges,
+			},
+			"pageInfo": &graphql.Field{
+				Type:    graphql.NewNonNull(pageInfoType),
+				Resolve: resolvePageInfo,
+			},
+			"totalCount": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Count of all the people matching the filter",
+				Resolve:     resolveTotalCount,
+			},
+		},
+	})
+
+	searchResultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchResult",
+		Fields: graphql.Fields{
+			"person": &graphql.Field{
+				Type: personType,
+			},
+			"score": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Trigram similarity of the query to the full name from 0 to 1",
+			},
+			"distance": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Levenshtein distance of the query to the closest of the name, surname and patronymic",
+			},
+		},
+	})
+
+	facetType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Facet",
+		Fields: graphql.Fields{
+			"value": &graphql.Field{
+				Type: graphql.String,
+			},
+			"count": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	})
+
+	facetsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Facets",
+		Fields: graphql.Fields{
+			"gender": &graphql.Field{
+				Type:        graphql.NewList(facetType),
+				Description: "Counts by the gender, unknown counts the people without the gender",
+			},
+			"nationality": &graphql.Field{
+				Type:        graphql.NewList(facetType),
+				Description: "Counts by the nationality, unknown counts the people without the nationality",
+			},
+			"age": &graphql.Field{
+				Type:        graphql.NewList(facetType),
+				Description: "Counts by the age range like 18-24, unknown counts the people without the age",
+			},
+		},
+	})
+
+	peopleStatsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PeopleStats",
+		Fields: graphql.Fields{
+			"total": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Count of all the people matching the filter",
+			},
+			"facets": &graphql.Field{
+				Type: facetsType,
+			},
+		},
+	})
+
+	ageBucketType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "AgeBucket",
+		Fields: graphql.Fields{
+			"from": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"to": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"count": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	})
+
+	nationalityStatisticsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "NationalityStatistics",
+		Fields: graphql.Fields{
+			"nationality": &graphql.Field{
+				Type: graphql.String,
+			},
+			"count": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"meanAge": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"medianAge": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"maleRatio": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Share of the men among the people of the known gender from 0 to 1",
+			},
+		},
+	})
+
+	periodCountType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PeriodCount",
+		Fields: graphql.Fields{
+			"period": &graphql.Field{
+				Type:        graphql.DateTime,
+				Description: "Start of the period",
+			},
+			"count": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Count of the people created in the period",
+			},
+			"total": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Count of the people created by the end of the period",
+			},
+		},
+	})
+
+	statisticsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Statistics",
+		Fields: graphql.Fields{
+			"ageHistogram": &graphql.Field{
+				Type: graphql.NewList(ageBucketType),
+			},
+			"nationalities": &graphql.Field{
+				Type:        graphql.NewList(nationalityStatisticsType),
+				Description: "Age and gender ratio of each nationality ordered by the count",
+			},
+			"timeline": &graphql.Field{
+				Type:        graphql.NewList(periodCountType),
+				Description: "Count of the created people over time",
+			},
+		},
+	})
+
+	requestType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Request",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.String,
+			},
+			"status": &graphql.Field{
+				Type:        graphql.String,
+				Description: "pending, stored or failed",
+			},
+			"personId": &graphql.Field{
+				Type: graphql.String,
+			},
+			"error": &graphql.Field{
+				Type: graphql.String,
+			},
+			"createdAt": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"updatedAt": &graphql.Field{
+				Type: graphql.DateTime,
+			},
 		},
 	})
 
@@ -53,7 +344,7 @@ func New(log *slog.Logger, svc PeopleServicer) (graphql.Schema, error) {
 		Fields: graphql.Fields{
 			"createPerson": &graphql.Field{
 				Type:        personType,
-				Description: "Create person",
+				Description: "Create person and wait until it is stored",
 				Args: graphql.FieldConfigArgument{
 					"name": &graphql.ArgumentConfig{
 						Type:        graphql.NewNonNull(graphql.String),
@@ -133,44 +424,96 @@ func New(log *slog.Logger, svc PeopleServicer) (graphql.Schema, error) {
 				},
 				Resolve: One(log, svc),
 			},
+			"request": &graphql.Field{
+				Type:        requestType,
+				Description: "Get the state of the request to create a person",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "Id",
+					},
+				},
+				Resolve: Request(log, svc),
+			},
 			"people": &graphql.Field{
 				Type:        graphql.NewList(personType),
 				Description: "Get people",
+				Args: filterArgs(graphql.FieldConfigArgument{
+					"orderBy": orderByArg,
+					"limit": &graphql.ArgumentConfig{
+						Type:        graphql.Int,
+						Description: "Limit",
+					},
+					"skip": &graphql.ArgumentConfig{
+						Type:        graphql.Int,
+						Description: "Skip",
+					},
+				}),
+				Resolve: List(log, svc),
+			},
+			"searchPeople": &graphql.Field{
+				Type:        graphql.NewList(searchResultType),
+				Description: "Search people by the name, surname and patronymic tolerating the typos and the transliteration",
 				Args: graphql.FieldConfigArgument{
-					"name": &graphql.ArgumentConfig{
-						Type:        graphql.String,
-						Description: "Name",
+					"q": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "Query",
 					},
-					"surname": &graphql.ArgumentConfig{
-						Type:        graphql.String,
-						Description: "Surname",
+					"limit": &graphql.ArgumentConfig{
+						Type:        graphql.Int,
+						Description: "Limit",
 					},
-					"patronymic": &graphql.ArgumentConfig{
-						Type:        graphql.String,
-						Description: "Patronymic",
+					"minScore": &graphql.ArgumentConfig{
+						Type:        graphql.Float,
+						Description: "Minimal similarity of the found people",
 					},
-					"age": &graphql.ArgumentConfig{
+				},
+				Resolve: Search(log, svc),
+			},
+			"peopleConnection": &graphql.Field{
+				Type:        peopleConnectionType,
+				Description: "Get the page of people by the cursor",
+				Args: filterArgs(graphql.FieldConfigArgument{
+					"orderBy": orderByArg,
+					"first": &graphql.ArgumentConfig{
 						Type:        graphql.Int,
-						Description: "Age",
+						Description: "Count of people after the cursor",
 					},
-					"gender": &graphql.ArgumentConfig{
+					"after": &graphql.ArgumentConfig{
 						Type:        graphql.String,
-						Description: "Gender",
+						Description: "Cursor the page starts after",
 					},
-					"nationality": &graphql.ArgumentConfig{
+					"last": &graphql.ArgumentConfig{
+						Type:        graphql.Int,
+						Description: "Count of people before the cursor",
+					},
+					"before": &graphql.ArgumentConfig{
 						Type:        graphql.String,
-						Description: "Nationality",
+						Description: "Cursor the page ends before",
 					},
-					"limit": &graphql.ArgumentConfig{
+				}),
+				Resolve: Connection(log, svc),
+			},
+			"peopleStats": &graphql.Field{
+				Type:        peopleStatsType,
+				Description: "Count people by the gender, nationality and age range",
+				Args:        filterArgs(nil),
+				Resolve:     Stats(log, svc),
+			},
+			"statistics": &graphql.Field{
+				Type:        statisticsType,
+				Description: "Get the demographics of the people",
+				Args: graphql.FieldConfigArgument{
+					"ageBucket": &graphql.ArgumentConfig{
 						Type:        graphql.Int,
-						Description: "Limit",
+						Description: "Width of the age histogram bucket, 10 by default",
 					},
-					"skip": &graphql.ArgumentConfig{
-						Type:        graphql.Int,
-						Description: "Skip",
+					"interval": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "Period the people are counted by over time: day, week, month or year",
 					},
 				},
-				Resolve: List(log, svc),
+				Resolve: Statistics(log, svc),
 			},
 		},
 	})
@@ -180,3 +523,89 @@ func New(log *slog.Logger, svc PeopleServicer) (graphql.Schema, error) {
 		Mutation: mutation,
 	})
 }
+
+// orderByArg is the argument of the sort order of the listed people.
+var orderByArg = &graphql.ArgumentConfig{
+	Type:        graphql.String,
+	Description: "Sort order like \"surname,-age\" by name, surname, patronymic, age, gender or nationality",
+}
+
+// filterArgs returns the arguments of the filter of the people with the arguments of the page.
+func filterArgs(page graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		"q": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Free text matching the name, surname or patronymic by each of its words",
+		},
+		"match": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Mode of the search by the name, surname and patronymic: exact, prefix or contains",
+		},
+		"ignoreCase": &graphql.ArgumentConfig{
+			Type:        graphql.Boolean,
+			Description: "Search by the name, surname and patronymic ignoring the case",
+		},
+		"name": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Name",
+		},
+		"surname": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Surname",
+		},
+		"patronymic": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Patronymic",
+		},
+		"age": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "Age",
+		},
+		"ageMin": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "Minimal age",
+		},
+		"ageMax": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "Maximal age",
+		},
+		"gender": &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.String),
+			Description: "Any of the genders, unknown matches the people without the gender",
+		},
+		"notGender": &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.String),
+			Description: "None of the genders, unknown excludes the people without the gender",
+		},
+		"nationality": &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.String),
+			Description: "Any of the nationalities",
+		},
+		"notNationality": &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.String),
+			Description: "None of the nationalities",
+		},
+		"nationalityThreshold": &graphql.ArgumentConfig{
+			Type:        graphql.Float,
+			Description: "Minimal probability of the nationality candidate",
+		},
+		"minAgeCount": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "Minimal count of samples of the age",
+		},
+		"minGenderProbability": &graphql.ArgumentConfig{
+			Type:        graphql.Float,
+			Description: "Minimal probability of the gender",
+		},
+		"minNationalityProbability": &graphql.ArgumentConfig{
+			Type:        graphql.Float,
+			Description: "Minimal probability of the nationality",
+		},
+	}
+
+	for name, arg := range page {
+		args[name] = arg
+	}
+
+	return args
+}
@@ -18,13 +18,31 @@ import (
 
 //go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name PersonLister --output ./mocks --outpkg mocks
 type PersonLister interface {
-	List(ctx context.Context, filter *models.Filter, query string) ([]models.Person, error)
+	List(ctx context.Context, filter *models.Filter, query string) (*models.Page, error)
 }
 
-func New(log *slog.Logger, lister PersonLister) func(http.ResponseWriter, *http.Request) {
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name PersonCounter --output ./mocks --outpkg mocks
+type PersonCounter interface {
+	Count(ctx context.Context, filter *models.Filter) (int, error)
+}
+
+// PersonAggregator counts the people matching the filter regardless of the page.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name PersonAggregator --output ./mocks --outpkg mocks
+type PersonAggregator interface {
+	PersonCounter
+	Stats(ctx context.Context, filter *models.Filter) (*models.Stats, error)
+}
+
+// New returns the handler of the page of the people,
+// the total count and the facets are added if the filter includes them.
+func New(log *slog.Logger, lister PersonLister, aggregator PersonAggregator) func(http.ResponseWriter, *http.Request) {
 	type res struct {
 		response.Response
-		People []models.Person `json:"people,omitempty"`
+		People   []models.Person  `json:"people,omitempty"`
+		PageInfo *models.PageInfo `json:"page_info,omitempty"`
+		Total    *int             `json:"total,omitempty"`
+		Facets   *models.Facets   `json:"facets,omitempty"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		log := log.With(
@@ -60,12 +78,12 @@ func New(log *slog.Logger, lister PersonLister) func(http.ResponseWriter, *http.
 			log.Error(msg, sl.Err(err))
 
 			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, res{Response: response.Error(err.Error())})
+			render.JSON(w, r, res{Response: response.ValidationError(err)})
 
 			return
 		}
 
-		p, err := lister.List(r.Context(), filter, r.URL.Query().Encode())
+		p, err := lister.List(r.Context(), filter, filter.String())
 		if err != nil {
 			if errors.Is(err, person.ErrNotFoundMany) {
 				msg := "people not found"
@@ -77,6 +95,16 @@ func New(log *slog.Logger, lister PersonLister) func(http.ResponseWriter, *http.
 
 				return
 			}
+			if errors.Is(err, models.ErrCursor) {
+				msg := "invalid request"
+
+				log.Error(msg, sl.Err(err), slog.Any("filter", filter))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, res{Response: response.Error(models.ErrCursor.Error())})
+
+				return
+			}
 			msg := "failed to get people"
 
 			log.Error(msg, sl.Err(err), slog.Any("filter", filter))
@@ -87,11 +115,43 @@ func New(log *slog.Logger, lister PersonLister) func(http.ResponseWriter, *http.
 			return
 		}
 
-		log.Info("people found", slog.Any("people", p))
+		log.Info("people found", slog.Any("people", p.People))
 
-		render.JSON(w, r, res{
+		out := res{
 			Response: response.OK(),
-			People:   p,
-		})
+			People:   p.People,
+			PageInfo: &p.PageInfo,
+		}
+
+		switch {
+		case filter.Includes(models.IncludeFacets):
+			stats, err := aggregator.Stats(r.Context(), filter)
+			if err != nil {
+				msg := "failed to get people stats"
+
+				log.Error(msg, sl.Err(err), slog.Any("filter", filter))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, res{Response: response.Error(msg)})
+
+				return
+			}
+			out.Total, out.Facets = &stats.Total, &stats.Facets
+		case filter.Includes(models.IncludeTotal):
+			total, err := aggregator.Count(r.Context(), filter)
+			if err != nil {
+				msg := "failed to count people"
+
+				log.Error(msg, sl.Err(err), slog.Any("filter", filter))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, res{Response: response.Error(msg)})
+
+				return
+			}
+			out.Total = &total
+		}
+
+		render.JSON(w, r, out)
 	}
 }
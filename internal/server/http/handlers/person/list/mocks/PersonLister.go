@@ -16,19 +16,19 @@ type PersonLister struct {
 }
 
 // List provides a mock function with given fields: ctx, filter, query
-func (_m *PersonLister) List(ctx context.Context, filter *models.Filter, query string) ([]models.Person, error) {
+func (_m *PersonLister) List(ctx context.Context, filter *models.Filter, query string) (*models.Page, error) {
 	ret := _m.Called(ctx, filter, query)
 
-	var r0 []models.Person
+	var r0 *models.Page
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter, string) ([]models.Person, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter, string) (*models.Page, error)); ok {
 		return rf(ctx, filter, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter, string) []models.Person); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter, string) *models.Page); ok {
 		r0 = rf(ctx, filter, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Person)
+			r0 = ret.Get(0).(*models.Page)
 		}
 	}
 
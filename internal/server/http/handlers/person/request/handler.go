@@ -0,0 +1,83 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/insan1a/exile/internal/lib/sl"
+	"github.com/insan1a/exile/internal/lib/validator"
+	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/server/http/api/response"
+	"github.com/insan1a/exile/internal/storage/request"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name RequestGetter --output ./mocks --outpkg mocks
+type RequestGetter interface {
+	GetRequest(ctx context.Context, id string) (*models.Request, error)
+}
+
+// New returns the handler reporting the state of the request to save a person.
+func New(log *slog.Logger, getter RequestGetter) func(http.ResponseWriter, *http.Request) {
+	type req struct {
+		ID string `validate:"required,uuid"`
+	}
+
+	type resp struct {
+		response.Response
+		*models.Request `json:"request,omitempty"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		input := req{ID: chi.URLParam(r, "id")}
+
+		log := log.With(
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			slog.String("person_request_id", input.ID),
+		)
+
+		if err := validator.ValidateStruct(input); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp{Response: response.ValidationError(err)})
+
+			return
+		}
+
+		pr, err := getter.GetRequest(r.Context(), input.ID)
+		if err != nil {
+			if errors.Is(err, request.ErrNotFound) {
+				msg := "the request not found"
+
+				log.Error(msg, sl.Err(err))
+
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp{Response: response.Error(msg)})
+
+				return
+			}
+
+			msg := "failed to get request"
+
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp{Response: response.Error(msg)})
+
+			return
+		}
+
+		log.Info("request found", slog.Any("request", pr))
+
+		render.JSON(w, r, resp{
+			Response: response.OK(),
+			Request:  pr,
+		})
+	}
+}
@@ -3,8 +3,10 @@ package save
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -13,18 +15,32 @@ import (
 	"github.com/insan1a/exile/internal/lib/validator"
 	"github.com/insan1a/exile/internal/models"
 	"github.com/insan1a/exile/internal/server/http/api/response"
+	"github.com/insan1a/exile/internal/service"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name PersonSaver --output ./mocks --outpkg mocks
 type PersonSaver interface {
-	Save(ctx context.Context, p models.Person) error
+	Save(ctx context.Context, p models.Person) (string, error)
+	SaveAndWait(ctx context.Context, p models.Person) (string, *models.Person, error)
 }
 
+// New returns the handler creating a new person.
+//
+// By default the person is sent to the person service and the ID of
+// the request is returned. If the "wait" query param is true the handler
+// waits for the person to be stored and returns it. If the person is
+// not stored in time the ID of the request is returned with 202 status.
 func New(log *slog.Logger, saver PersonSaver) func(http.ResponseWriter, *http.Request) {
 	type req struct {
-		Name       string `json:"name" validate:"required,alpha"`
-		Surname    string `json:"surname" validate:"required,alpha"`
-		Patronymic string `json:"patronymic" validate:"omitempty,alpha"`
+		Name       string `json:"name" validate:"required,personname"`
+		Surname    string `json:"surname" validate:"required,personname"`
+		Patronymic string `json:"patronymic" validate:"omitempty,personname"`
+	}
+
+	type resp struct {
+		response.Response
+		RequestID string         `json:"request_id,omitempty"`
+		Person    *models.Person `json:"person,omitempty"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -39,7 +55,7 @@ func New(log *slog.Logger, saver PersonSaver) func(http.ResponseWriter, *http.Re
 			log.Error(msg, sl.Err(err))
 
 			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, response.Error(msg))
+			render.JSON(w, r, resp{Response: response.Error(msg)})
 
 			return
 		}
@@ -50,32 +66,85 @@ func New(log *slog.Logger, saver PersonSaver) func(http.ResponseWriter, *http.Re
 			log.Error(msg, sl.Err(err), slog.Any("request_body", input))
 
 			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, response.Error(err.Error()))
+			render.JSON(w, r, resp{Response: response.ValidationError(err)})
 
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(r.Context(), 100*time.Millisecond)
-		defer cancel()
+		wait, _ := strconv.ParseBool(r.URL.Query().Get("wait"))
 
-		err := saver.Save(ctx, models.Person{
+		p := models.Person{
 			Name:       input.Name,
 			Surname:    input.Surname,
 			Patronymic: input.Patronymic,
-		})
-		if err != nil {
-			msg := "failed to save person"
+		}
 
-			log.Error(msg, sl.Err(err), slog.Any("request_body", input))
+		if !wait {
+			ctx, cancel := context.WithTimeout(r.Context(), 100*time.Millisecond)
+			defer cancel()
+
+			id, err := saver.Save(ctx, p)
+			if err != nil {
+				msg := "failed to save person"
+
+				log.Error(msg, sl.Err(err), slog.Any("request_body", input))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp{Response: response.Error(msg)})
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(msg))
+				return
+			}
+
+			log.Info("the person successfully saved", slog.Any("request_body", input), slog.String("person_request_id", id))
+
+			render.JSON(w, r, resp{
+				Response:  response.OK(),
+				RequestID: id,
+			})
+
+			return
+		}
+
+		id, stored, err := saver.SaveAndWait(r.Context(), p)
+		if err != nil {
+			var reqErr *service.RequestError
+
+			switch {
+			case errors.Is(err, service.ErrReplyTimeout), errors.Is(err, service.ErrRepliesDisabled):
+				log.Info("the person is still processing", sl.Err(err), slog.String("person_request_id", id))
+
+				render.Status(r, http.StatusAccepted)
+				render.JSON(w, r, resp{
+					Response:  response.OK(),
+					RequestID: id,
+				})
+			case errors.As(err, &reqErr):
+				log.Error("the person service rejected the person", sl.Err(err), slog.String("person_request_id", id))
+
+				render.Status(r, http.StatusUnprocessableEntity)
+				render.JSON(w, r, resp{
+					Response:  response.Error(reqErr.Reason),
+					RequestID: id,
+				})
+			default:
+				msg := "failed to save person"
+
+				log.Error(msg, sl.Err(err), slog.Any("request_body", input))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp{Response: response.Error(msg)})
+			}
 
 			return
 		}
 
-		log.Info("the person successfully saved", slog.Any("request_body", input))
+		log.Info("the person successfully stored", slog.Any("person", stored), slog.String("person_request_id", id))
 
-		render.JSON(w, r, response.OK())
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, resp{
+			Response:  response.OK(),
+			RequestID: id,
+			Person:    stored,
+		})
 	}
 }
@@ -15,17 +15,60 @@ type PersonSaver struct {
 }
 
 // Save provides a mock function with given fields: ctx, p
-func (_m *PersonSaver) Save(ctx context.Context, p models.Person) error {
+func (_m *PersonSaver) Save(ctx context.Context, p models.Person) (string, error) {
 	ret := _m.Called(ctx, p)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.Person) error); ok {
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Person) (string, error)); ok {
+		return rf(ctx, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Person) string); ok {
 		r0 = rf(ctx, p)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Person) error); ok {
+		r1 = rf(ctx, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveAndWait provides a mock function with given fields: ctx, p
+func (_m *PersonSaver) SaveAndWait(ctx context.Context, p models.Person) (string, *models.Person, error) {
+	ret := _m.Called(ctx, p)
+
+	var r0 string
+	var r1 *models.Person
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Person) (string, *models.Person, error)); ok {
+		return rf(ctx, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Person) string); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Person) *models.Person); ok {
+		r1 = rf(ctx, p)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*models.Person)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, models.Person) error); ok {
+		r2 = rf(ctx, p)
+	} else {
+		r2 = ret.Error(2)
 	}
 
-	return r0
+	return r0, r1, r2
 }
 
 type mockConstructorTestingTNewPersonSaver interface {
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/gorilla/schema"
+	"github.com/insan1a/exile/internal/lib/sl"
+	"github.com/insan1a/exile/internal/lib/validator"
+	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/server/http/api/response"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name PersonSearcher --output ./mocks --outpkg mocks
+type PersonSearcher interface {
+	Search(ctx context.Context, search *models.Search) ([]models.SearchResult, error)
+}
+
+func New(log *slog.Logger, searcher PersonSearcher) func(http.ResponseWriter, *http.Request) {
+	type res struct {
+		response.Response
+		Results []models.SearchResult `json:"results,omitempty"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		search := new(models.Search)
+		if err := schema.NewDecoder().Decode(search, r.URL.Query()); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, res{Response: response.Error(msg)})
+
+			return
+		}
+
+		if err := validator.ValidateStruct(*search); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, res{Response: response.ValidationError(err)})
+
+			return
+		}
+
+		results, err := searcher.Search(r.Context(), search)
+		if err != nil {
+			msg := "failed to search people"
+
+			log.Error(msg, sl.Err(err), slog.Any("search", search))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, res{Response: response.Error(msg)})
+
+			return
+		}
+
+		log.Info("people found", slog.Any("search", search), slog.Int("count", len(results)))
+
+		render.JSON(w, r, res{
+			Response: response.OK(),
+			Results:  results,
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/gorilla/schema"
+	"github.com/insan1a/exile/internal/lib/sl"
+	"github.com/insan1a/exile/internal/lib/validator"
+	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/server/http/api/response"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name StatisticsGetter --output ./mocks --outpkg mocks
+type StatisticsGetter interface {
+	Statistics(ctx context.Context, statistics *models.Statistics) (*models.StatisticsResult, error)
+}
+
+func New(log *slog.Logger, getter StatisticsGetter) func(http.ResponseWriter, *http.Request) {
+	type res struct {
+		response.Response
+		*models.StatisticsResult
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		statistics := new(models.Statistics)
+		if err := schema.NewDecoder().Decode(statistics, r.URL.Query()); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, res{Response: response.Error(msg)})
+
+			return
+		}
+
+		if err := validator.ValidateStruct(*statistics); err != nil {
+			msg := "invalid request"
+
+			log.Error(msg, sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, res{Response: response.ValidationError(err)})
+
+			return
+		}
+
+		result, err := getter.Statistics(r.Context(), statistics)
+		if err != nil {
+			msg := "failed to get statistics"
+
+			log.Error(msg, sl.Err(err), slog.Any("statistics", statistics))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, res{Response: response.Error(msg)})
+
+			return
+		}
+
+		log.Info("statistics found", slog.Any("statistics", statistics))
+
+		render.JSON(w, r, res{
+			Response:         response.OK(),
+			StatisticsResult: result,
+		})
+	}
+}
@@ -24,9 +24,9 @@ type PersonUpdater interface {
 
 func New(log *slog.Logger, updater PersonUpdater) func(http.ResponseWriter, *http.Request) {
 	type req struct {
-		Name        string `json:"name" validate:"omitempty,alpha"`
-		Surname     string `json:"surname" validate:"omitempty,alpha"`
-		Patronymic  string `json:"patronymic" validate:"omitempty,alpha"`
+		Name        string `json:"name" validate:"omitempty,personname"`
+		Surname     string `json:"surname" validate:"omitempty,personname"`
+		Patronymic  string `json:"patronymic" validate:"omitempty,personname"`
 		Age         int    `json:"age" validate:"omitempty,gte=0,lte=150"`
 		Gender      string `json:"gender" validate:"omitempty,oneof=male female"`
 		Nationality string `json:"nationality" validate:"omitempty,alpha,len=2"`
@@ -62,7 +62,7 @@ func New(log *slog.Logger, updater PersonUpdater) func(http.ResponseWriter, *htt
 			log.Error(msg, sl.Err(err))
 
 			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp{Response: response.Error(msg)})
+			render.JSON(w, r, resp{Response: response.ValidationError(err)})
 
 			return
 		}
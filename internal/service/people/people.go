@@ -7,6 +7,8 @@ import (
 	"time"
 
 	kfk "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/google/uuid"
+	"github.com/insan1a/exile/internal/client"
 	"github.com/insan1a/exile/internal/lib/validator"
 	"github.com/insan1a/exile/internal/models"
 	"github.com/insan1a/exile/internal/service"
@@ -17,6 +19,8 @@ import (
 	"github.com/insan1a/exile/internal/storage/cache/redis"
 	"github.com/insan1a/exile/internal/storage/person"
 	"github.com/insan1a/exile/internal/storage/person/pg"
+	"github.com/insan1a/exile/internal/storage/request"
+	requestpg "github.com/insan1a/exile/internal/storage/request/pg"
 )
 
 // Option represents the option for people service
@@ -51,6 +55,35 @@ func WithPostgresPersonStorage(url string) Option {
 	}
 }
 
+// WithRequestStorage injects request storage into the people service
+func WithRequestStorage(requests request.Storage) Option {
+	return func(s *Service) error {
+		if requests == nil {
+			return service.ErrNilRequestStorage
+		}
+
+		s.requests = requests
+		return nil
+	}
+}
+
+// WithPostgresRequestStorage injects postgres request storage into the people service
+func WithPostgresRequestStorage(url string) Option {
+	return func(s *Service) error {
+		db, err := storage.NewPostgresPool(url)
+		if err != nil {
+			return err
+		}
+
+		requests, err := requestpg.New(db)
+		if err != nil {
+			return err
+		}
+
+		return WithRequestStorage(requests)(s)
+	}
+}
+
 func WithCache(c cache.Cache, ttl time.Duration) Option {
 	return func(s *Service) error {
 		s.cache = c
@@ -105,15 +138,55 @@ func WithKafkaProducer(cfg *kfk.ConfigMap, topic string) Option {
 	}
 }
 
+// WithReplyConsumer injects the consumer of the replies from the person service.
+//
+// The replies are used to wait for the result of the saving in SaveAndWait.
+func WithReplyConsumer(consumer broker.Consumer, timeout time.Duration) Option {
+	return func(s *Service) error {
+		if consumer == nil {
+			return service.ErrNilConsumer
+		}
+
+		s.replies = consumer
+		s.replyTimeout = timeout
+		return nil
+	}
+}
+
+// WithKafkaReplyConsumer injects kafka consumer of the reply topic into the people service
+func WithKafkaReplyConsumer(cfg *kfk.ConfigMap, topic string, timeout time.Duration) Option {
+	return func(s *Service) error {
+		if cfg == nil {
+			return service.ErrNilKafkaConfig
+		}
+
+		kafkaConsumer, err := storage.NewKafkaConsumer(cfg, []string{topic})
+		if err != nil {
+			return err
+		}
+
+		return WithReplyConsumer(brokerkafka.NewConsumer(kafkaConsumer), timeout)(s)
+	}
+}
+
 // Service represents the people service
 type Service struct {
-	people person.Storage `validate:"required"`
+	people   person.Storage `validate:"required"`
+	requests request.Storage
 
 	cache    cache.Cache `validate:"required"`
 	cacheTTL time.Duration
+	// generation versions the cached pages and statistics, it is bumped on the writes.
+	generation *cache.Generation
 
 	producer broker.Producer
 	topic    string
+
+	replies      broker.Consumer
+	replyTimeout time.Duration
+	waiters      *waiters
+	stop         chan struct{}
+	stopped      chan struct{}
 }
 
 // New creates a new people service with given Options.
@@ -130,20 +203,111 @@ func New(opts ...Option) (*Service, error) {
 		return nil, err
 	}
 
+	s.generation = cache.NewGeneration(s.cache, models.PeopleGeneration)
+
+	if s.replies != nil {
+		s.waiters = newWaiters()
+		s.stop = make(chan struct{})
+		s.stopped = make(chan struct{})
+		go s.listen()
+	}
+
 	return s, nil
 }
 
-func (s *Service) Save(ctx context.Context, p models.Person) error {
+// Save sends the person to the person service and returns the ID of the request.
+//
+// The ID of the request is used as the key of the message.
+func (s *Service) Save(ctx context.Context, p models.Person) (string, error) {
+	id := uuid.NewString()
+
+	if err := s.produce(ctx, id, p); err != nil {
+		return "", fmt.Errorf("Service.Save: %w", err)
+	}
+
+	return id, nil
+}
+
+// SaveAndWait sends the person to the person service and waits for the reply.
+//
+// Returns the ID of the request and the stored person.
+// If the reply is not received in time service.ErrReplyTimeout is returned.
+// If the replies are disabled service.ErrRepliesDisabled is returned.
+// If the person service failed to process the request *service.RequestError is returned.
+func (s *Service) SaveAndWait(ctx context.Context, p models.Person) (string, *models.Person, error) {
+	if s.replies == nil {
+		id, err := s.Save(ctx, p)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return id, nil, fmt.Errorf("Service.SaveAndWait: %w", service.ErrRepliesDisabled)
+	}
+
+	id := uuid.NewString()
+
+	ch := s.waiters.add(id)
+	defer s.waiters.remove(id)
+
+	if err := s.produce(ctx, id, p); err != nil {
+		return "", nil, fmt.Errorf("Service.SaveAndWait: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.replyTimeout)
+	defer cancel()
+
+	select {
+	case reply := <-ch:
+		if reply.Status == models.RequestStatusFailed {
+			return id, nil, fmt.Errorf("Service.SaveAndWait: %w", &service.RequestError{
+				ID:     id,
+				Reason: reply.Error,
+			})
+		}
+
+		return id, reply.Person, nil
+	case <-ctx.Done():
+		return id, nil, fmt.Errorf("Service.SaveAndWait: %w", service.ErrReplyTimeout)
+	}
+}
+
+// produce sends the normalized person with the request ID as the key.
+//
+// If the request storage is set, the request is tracked as pending.
+func (s *Service) produce(ctx context.Context, id string, p models.Person) error {
+	p.Normalize()
+
 	mp, err := json.Marshal(&p)
 	if err != nil {
-		return fmt.Errorf("Service.Save: %w", err)
+		return err
 	}
 
-	if err = s.producer.Produce(mp); err != nil {
-		return fmt.Errorf("Service.Save: %w", err)
+	if s.requests != nil {
+		if err = s.requests.Create(ctx, &models.Request{
+			ID:     id,
+			Status: models.RequestStatusPending,
+		}); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return s.producer.ProduceMessage(&broker.Message{Key: id, Value: mp})
+}
+
+// GetRequest returns the state of the request to save a person.
+//
+// If the request storage is not set request.ErrNotFound is returned.
+func (s *Service) GetRequest(ctx context.Context, id string) (*models.Request, error) {
+	if s.requests == nil {
+		return nil, fmt.Errorf("Service.GetRequest: %w", request.ErrNotFound)
+	}
+
+	r, err := s.requests.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("Service.GetRequest: %w", err)
+	}
+
+	return r, nil
 }
 
 func (s *Service) Get(ctx context.Context, id string) (*models.Person, error) {
@@ -172,21 +336,144 @@ func (s *Service) Get(ctx context.Context, id string) (*models.Person, error) {
 	return p, nil
 }
 
-func (s *Service) List(ctx context.Context, filter *models.Filter, query string) ([]models.Person, error) {
+// List returns the page of the people matching the filter,
+// the page is cached by the query which must identify the filter with its sort order
+// until any person is created, updated or deleted.
+func (s *Service) List(ctx context.Context, filter *models.Filter, query string) (*models.Page, error) {
+	key, err := s.generation.Key(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Service.List: %w", err)
+	}
+
+	v, found, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("Service.List: %w", err)
+	}
+	if found {
+		p := new(models.Page)
+		if err = json.Unmarshal(v, p); err != nil {
+			return nil, fmt.Errorf("Service.List: %w", err)
+		}
+		return p, nil
+	}
+
 	p, err := s.people.List(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("Service.List: %w", err)
 	}
 
+	mp, _ := json.Marshal(p)
+	if err = s.cache.Set(ctx, key, mp, s.cacheTTL); err != nil {
+		return nil, fmt.Errorf("Service.List: %w", err)
+	}
+
 	return p, nil
 }
 
+// Count returns the count of all the people matching the filter.
+func (s *Service) Count(ctx context.Context, filter *models.Filter) (int, error) {
+	count, err := s.people.Count(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("Service.Count: %w", err)
+	}
+
+	return count, nil
+}
+
+// Stats returns the total count and the facets of the people matching the filter.
+func (s *Service) Stats(ctx context.Context, filter *models.Filter) (*models.Stats, error) {
+	stats, err := s.people.Stats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("Service.Stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Statistics returns the demographics of the stored people,
+// they are cached until any person is created, updated or deleted.
+func (s *Service) Statistics(ctx context.Context, statistics *models.Statistics) (*models.StatisticsResult, error) {
+	key, err := s.generation.Key(ctx, statistics.String())
+	if err != nil {
+		return nil, fmt.Errorf("Service.Statistics: %w", err)
+	}
+
+	v, found, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("Service.Statistics: %w", err)
+	}
+	if found {
+		r := new(models.StatisticsResult)
+		if err = json.Unmarshal(v, r); err != nil {
+			return nil, fmt.Errorf("Service.Statistics: %w", err)
+		}
+		return r, nil
+	}
+
+	r, err := s.people.Statistics(ctx, statistics)
+	if err != nil {
+		return nil, fmt.Errorf("Service.Statistics: %w", err)
+	}
+
+	mr, _ := json.Marshal(r)
+	if err = s.cache.Set(ctx, key, mr, s.cacheTTL); err != nil {
+		return nil, fmt.Errorf("Service.Statistics: %w", err)
+	}
+
+	return r, nil
+}
+
+// Search returns the people found by the typo tolerant search ordered by their relevance.
+func (s *Service) Search(ctx context.Context, search *models.Search) ([]models.SearchResult, error) {
+	results, err := s.people.Search(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("Service.Search: %w", err)
+	}
+
+	return results, nil
+}
+
+// Update updates the person, the age, gender and nationality set by the update
+// are marked as manual so the re-enrichment never overwrites them.
+// The manual gender and nationality are certain, the manual nationality
+// replaces its candidates so the filters match it instead of the enriched one.
+// The name, surname and patronymic are normalized before storing.
 func (s *Service) Update(ctx context.Context, p *models.Person) error {
+	p.Normalize()
+
+	source := models.FieldSource{Source: models.SourceManual, UpdatedAt: time.Now()}
+	for f, set := range map[client.Field]bool{
+		client.FieldAge:         p.Age != 0,
+		client.FieldGender:      p.Gender != "",
+		client.FieldNationality: p.Nationality != "",
+	} {
+		if !set {
+			continue
+		}
+
+		if p.Provenance == nil {
+			p.Provenance = make(models.Provenance)
+		}
+		p.Provenance[string(f)] = source
+	}
+
+	if p.Gender != "" {
+		p.GenderProbability = 1
+	}
+	if p.Nationality != "" {
+		p.NationalityProbability = 1
+		p.Nationalities = []models.Nationality{{CountryID: p.Nationality, Probability: 1}}
+	}
+
 	if err := s.people.Update(ctx, p); err != nil {
 		return err
 	}
 
-	return s.cache.Del(ctx, p.ID)
+	if err := s.cache.Del(ctx, p.ID); err != nil {
+		return err
+	}
+
+	return s.generation.Bump(ctx)
 }
 
 func (s *Service) Delete(ctx context.Context, id string) error {
@@ -198,11 +485,24 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("Service.Delete: %w", err)
 	}
 
+	if err := s.generation.Bump(ctx); err != nil {
+		return fmt.Errorf("Service.Delete: %w", err)
+	}
+
 	return nil
 }
 
-// Close flushes and closes the producer
+// Close flushes and closes the producer and stops listening to the replies
 func (s *Service) Close() error {
+	if s.replies != nil {
+		close(s.stop)
+		<-s.stopped
+
+		if err := s.replies.Close(); err != nil {
+			return fmt.Errorf("Service.Close: %w", err)
+		}
+	}
+
 	if err := s.producer.Close(); err != nil {
 		return fmt.Errorf("Service.Close: %w", err)
 	}
@@ -1,15 +1,22 @@
 package people
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/service"
+	"github.com/insan1a/exile/internal/storage/broker"
 	brokermocks "github.com/insan1a/exile/internal/storage/broker/mocks"
 	cachemocks "github.com/insan1a/exile/internal/storage/cache/mocks"
 	storagemocks "github.com/insan1a/exile/internal/storage/person/mocks"
+	requestmocks "github.com/insan1a/exile/internal/storage/request/mocks"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNew(t *testing.T) {
@@ -71,14 +78,173 @@ func TestService_Save(t *testing.T) {
 	p := models.Person{}
 	msg, _ := json.Marshal(&p)
 
-	producer.On("Produce", msg).
+	producer.On("ProduceMessage", mock.MatchedBy(func(m *broker.Message) bool {
+		return m.Key != "" && bytes.Equal(m.Value, msg)
+	})).
 		Once().
 		Return(nil)
 
-	err = svc.Save(context.Background(), p)
+	id, err := svc.Save(context.Background(), p)
 	if err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
+
+	if id == "" {
+		t.Fatalf("Save() returned empty request id")
+	}
+}
+
+func TestService_SaveWithRequestStorage(t *testing.T) {
+	producer := brokermocks.NewProducer(t)
+	requests := requestmocks.NewStorage(t)
+
+	svc, err := New(
+		WithProducer(producer, ""),
+		WithRequestStorage(requests),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	var created string
+
+	requests.On("Create", ctx, mock.MatchedBy(func(r *models.Request) bool {
+		return r.Status == models.RequestStatusPending
+	})).
+		Once().
+		Run(func(args mock.Arguments) {
+			created = args.Get(1).(*models.Request).ID
+		}).
+		Return(nil)
+	producer.On("ProduceMessage", mock.MatchedBy(func(m *broker.Message) bool {
+		return m.Key == created
+	})).
+		Once().
+		Return(nil)
+
+	id, err := svc.Save(ctx, models.Person{Name: "Ivan"})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if id != created {
+		t.Errorf("Save() id = %v, want %v", id, created)
+	}
+}
+
+func TestService_GetRequest(t *testing.T) {
+	requests := requestmocks.NewStorage(t)
+
+	svc, err := New(WithRequestStorage(requests))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	uuid := "uuid"
+
+	requests.On("FindByID", ctx, uuid).
+		Once().
+		Return(&models.Request{ID: uuid, Status: models.RequestStatusPending}, nil)
+
+	r, err := svc.GetRequest(ctx, uuid)
+	if err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+
+	if r.Status != models.RequestStatusPending {
+		t.Errorf("GetRequest() status = %v, want %v", r.Status, models.RequestStatusPending)
+	}
+}
+
+func TestService_SaveAndWait(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   *models.ReplyMessage
+		wantErr error
+	}{
+		{
+			name:  "stored",
+			reply: &models.ReplyMessage{Status: models.RequestStatusStored, Person: &models.Person{ID: "uuid"}},
+		},
+		{
+			name:    "failed",
+			reply:   &models.ReplyMessage{Status: models.RequestStatusFailed, Error: "invalid name"},
+			wantErr: &service.RequestError{},
+		},
+		{
+			name:    "timeout",
+			wantErr: service.ErrReplyTimeout,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			producer := brokermocks.NewProducer(t)
+			consumer := brokermocks.NewConsumer(t)
+
+			svc, err := New(
+				WithProducer(producer, ""),
+				WithReplyConsumer(consumer, 100*time.Millisecond),
+			)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			keys := make(chan string, 1)
+			producer.On("ProduceMessage", mock.Anything).
+				Once().
+				Run(func(args mock.Arguments) {
+					keys <- args.Get(0).(*broker.Message).Key
+				}).
+				Return(nil)
+
+			consumer.On("Consume", mock.Anything).
+				Return(func(timeout time.Duration) (*broker.Message, error) {
+					select {
+					case key := <-keys:
+						if tt.reply == nil {
+							return nil, errors.New("timed out")
+						}
+
+						data, _ := json.Marshal(tt.reply)
+						return &broker.Message{Key: key, Value: data}, nil
+					case <-time.After(timeout):
+						return nil, errors.New("timed out")
+					}
+				})
+			consumer.On("Close").Once().Return(nil)
+			producer.On("Close").Once().Return(nil)
+
+			id, p, err := svc.SaveAndWait(context.Background(), models.Person{Name: "Ivan"})
+			if id == "" {
+				t.Errorf("SaveAndWait() returned empty request id")
+			}
+
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("SaveAndWait() error = %v", err)
+				}
+				if p == nil || p.ID != tt.reply.Person.ID {
+					t.Errorf("SaveAndWait() person = %v, want %v", p, tt.reply.Person)
+				}
+			case *service.RequestError:
+				if !errors.As(err, &want) {
+					t.Errorf("SaveAndWait() error = %v, want %T", err, want)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Errorf("SaveAndWait() error = %v, want %v", err, want)
+				}
+			}
+
+			if err := svc.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+		})
+	}
 }
 
 func TestService_Get(t *testing.T) {
@@ -131,17 +297,149 @@ func TestService_List(t *testing.T) {
 	ctx := context.Background()
 	filter := &models.Filter{Limit: 10, Skip: 0}
 	query := filter.String()
-	people := make([]models.Person, 0)
+	people := &models.Page{People: make([]models.Person, 0)}
 	data, _ := json.Marshal(people)
 
-	cache.On("Get", ctx, query).Once().Return(nil, false, nil)
+	cache.On("Get", ctx, models.PeopleGeneration).Once().Return(nil, false, nil)
+	cache.On("Get", ctx, query+"@0").Once().Return(nil, false, nil)
 	storage.On("List", ctx, filter).Once().Return(people, nil)
-	cache.On("Set", ctx, query, data, time.Minute).Once().Return(nil)
+	cache.On("Set", ctx, query+"@0", data, time.Minute).Once().Return(nil)
 
 	_, err = svc.List(ctx, filter, query)
 	if err != nil {
 		t.Fatalf("List() error = %v", err)
 	}
+
+	sorted := &models.Filter{Sort: "surname,-age"}
+	cached := &models.Page{People: []models.Person{{ID: "uuid", Surname: "Ivanov"}}}
+	data, _ = json.Marshal(cached)
+
+	cache.On("Get", ctx, models.PeopleGeneration).Once().Return([]byte("1"), true, nil)
+	cache.On("Get", ctx, sorted.String()+"@1").Once().Return(data, true, nil)
+
+	got, err := svc.List(ctx, sorted, sorted.String())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got.People) != 1 || got.People[0].Surname != "Ivanov" {
+		t.Errorf("List() = %v, want the cached page", got)
+	}
+}
+
+func TestService_Statistics(t *testing.T) {
+	storage := storagemocks.NewStorage(t)
+	cache := cachemocks.NewCache(t)
+
+	svc, err := New(
+		WithPersonStorage(storage),
+		WithCache(cache, time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	statistics := &models.Statistics{Interval: "week"}
+	want := &models.StatisticsResult{
+		AgeHistogram:  []models.AgeBucket{{From: 20, To: 29, Count: 2}},
+		Nationalities: []models.NationalityStatistics{{Nationality: "RU", Count: 2, MeanAge: 25, MedianAge: 25, MaleRatio: 0.5}},
+		Timeline:      []models.PeriodCount{},
+	}
+	data, _ := json.Marshal(want)
+
+	cache.On("Get", ctx, models.PeopleGeneration).Once().Return(nil, false, nil)
+	cache.On("Get", ctx, statistics.String()+"@0").Once().Return(nil, false, nil)
+	storage.On("Statistics", ctx, statistics).Once().Return(want, nil)
+	cache.On("Set", ctx, statistics.String()+"@0", data, time.Minute).Once().Return(nil)
+
+	got, err := svc.Statistics(ctx, statistics)
+	if err != nil {
+		t.Fatalf("Statistics() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Statistics() = %v, want %v", got, want)
+	}
+
+	cache.On("Get", ctx, models.PeopleGeneration).Once().Return([]byte("1"), true, nil)
+	cache.On("Get", ctx, statistics.String()+"@1").Once().Return(data, true, nil)
+
+	got, err = svc.Statistics(ctx, statistics)
+	if err != nil {
+		t.Fatalf("Statistics() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Statistics() = %v, want the cached %v", got, want)
+	}
+}
+
+func TestService_Count(t *testing.T) {
+	storage := storagemocks.NewStorage(t)
+
+	svc, err := New(WithPersonStorage(storage))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	filter := &models.Filter{Gender: models.List{"male"}}
+
+	storage.On("Count", ctx, filter).Once().Return(42, nil)
+
+	count, err := svc.Count(ctx, filter)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Count() = %d, want 42", count)
+	}
+}
+
+func TestService_Stats(t *testing.T) {
+	storage := storagemocks.NewStorage(t)
+
+	svc, err := New(WithPersonStorage(storage))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	filter := &models.Filter{AgeMin: 18}
+	want := &models.Stats{Total: 3, Facets: models.Facets{
+		Gender: []models.Facet{{Value: "male", Count: 2}, {Value: models.FacetUnknown, Count: 1}},
+	}}
+
+	storage.On("Stats", ctx, filter).Once().Return(want, nil)
+
+	got, err := svc.Stats(ctx, filter)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats() = %v, want %v", got, want)
+	}
+}
+
+func TestService_Search(t *testing.T) {
+	storage := storagemocks.NewStorage(t)
+
+	svc, err := New(WithPersonStorage(storage))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	search := &models.Search{Q: "Ivnaov"}
+	want := []models.SearchResult{{Person: models.Person{ID: "uuid", Surname: "Иванов"}, Score: 0.5, Distance: 2}}
+
+	storage.On("Search", ctx, search).Once().Return(want, nil)
+
+	got, err := svc.Search(ctx, search)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Search() = %v, want %v", got, want)
+	}
 }
 
 func TestService_Update(t *testing.T) {
@@ -160,11 +458,48 @@ func TestService_Update(t *testing.T) {
 
 	storage.On("Update", ctx, p).Once().Return(nil)
 	cache.On("Del", ctx, p.ID).Once().Return(nil)
+	cache.On("Set", ctx, models.PeopleGeneration, mock.Anything, time.Duration(0)).Once().Return(nil)
 
 	err = svc.Update(ctx, p)
 	if err != nil {
 		t.Fatalf("Update() error = %v", err)
 	}
+	if p.Provenance != nil {
+		t.Errorf("Update() provenance = %v, want nil", p.Provenance)
+	}
+
+	p = &models.Person{ID: "uuid", Age: 42, Nationality: "RU"}
+
+	storage.On("Update", ctx, p).Once().Return(nil)
+	cache.On("Del", ctx, p.ID).Once().Return(nil)
+	cache.On("Set", ctx, models.PeopleGeneration, mock.Anything, time.Duration(0)).Once().Return(nil)
+
+	if err = svc.Update(ctx, p); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !p.Provenance.Manual("age") || !p.Provenance.Manual("nationality") || p.Provenance.Manual("gender") {
+		t.Errorf("Update() provenance = %v, want manual age and nationality", p.Provenance)
+	}
+	wantNationalities := []models.Nationality{{CountryID: "RU", Probability: 1}}
+	if p.NationalityProbability != 1 || !reflect.DeepEqual(p.Nationalities, wantNationalities) {
+		t.Errorf("Update() nationality = %v %v, want the certain manual one", p.NationalityProbability, p.Nationalities)
+	}
+	if p.GenderProbability != 0 {
+		t.Errorf("Update() gender probability = %v, want it kept", p.GenderProbability)
+	}
+
+	p = &models.Person{ID: "uuid", Gender: "female"}
+
+	storage.On("Update", ctx, p).Once().Return(nil)
+	cache.On("Del", ctx, p.ID).Once().Return(nil)
+	cache.On("Set", ctx, models.PeopleGeneration, mock.Anything, time.Duration(0)).Once().Return(nil)
+
+	if err = svc.Update(ctx, p); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if p.GenderProbability != 1 || p.Nationalities != nil {
+		t.Errorf("Update() = %v, want the certain manual gender only", p)
+	}
 }
 
 func TestService_Delete(t *testing.T) {
@@ -183,6 +518,7 @@ func TestService_Delete(t *testing.T) {
 
 	storage.On("Delete", ctx, uuid).Once().Return(nil)
 	cache.On("Del", ctx, uuid).Once().Return(nil)
+	cache.On("Set", ctx, models.PeopleGeneration, mock.Anything, time.Duration(0)).Once().Return(nil)
 
 	if err = svc.Delete(ctx, uuid); err != nil {
 		t.Fatalf("Delete() error = %v", err)
@@ -0,0 +1,85 @@
+package people
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/insan1a/exile/internal/models"
+)
+
+// replyPollTimeout is the timeout of a single read from the reply topic.
+const replyPollTimeout = 100 * time.Millisecond
+
+// waiters holds the channels of the requests waiting for the reply.
+type waiters struct {
+	mu sync.Mutex
+	m  map[string]chan models.ReplyMessage
+}
+
+func newWaiters() *waiters {
+	return &waiters{m: make(map[string]chan models.ReplyMessage)}
+}
+
+func (w *waiters) add(id string) <-chan models.ReplyMessage {
+	ch := make(chan models.ReplyMessage, 1)
+
+	w.mu.Lock()
+	w.m[id] = ch
+	w.mu.Unlock()
+
+	return ch
+}
+
+func (w *waiters) remove(id string) {
+	w.mu.Lock()
+	delete(w.m, id)
+	w.mu.Unlock()
+}
+
+// resolve passes the reply to the request waiting for it.
+//
+// The replies to the requests nobody waits for are dropped.
+func (w *waiters) resolve(reply models.ReplyMessage) {
+	w.mu.Lock()
+	ch, ok := w.m[reply.RequestID]
+	w.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	select {
+	case ch <- reply:
+	default:
+	}
+}
+
+// listen reads the replies until the service is closed.
+func (s *Service) listen() {
+	defer close(s.stopped)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		msg, err := s.replies.Consume(replyPollTimeout)
+		if err != nil {
+			continue
+		}
+
+		var reply models.ReplyMessage
+		if err = json.Unmarshal(msg.Value, &reply); err != nil {
+			continue
+		}
+
+		if reply.RequestID == "" {
+			reply.RequestID = msg.Key
+		}
+
+		s.waiters.resolve(reply)
+	}
+}
@@ -4,23 +4,81 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/insan1a/exile/internal/client"
+	"github.com/insan1a/exile/internal/lib/retry"
 	"github.com/insan1a/exile/internal/lib/validator"
 	"github.com/insan1a/exile/internal/models"
 	"github.com/insan1a/exile/internal/service"
 	"github.com/insan1a/exile/internal/storage"
 	"github.com/insan1a/exile/internal/storage/broker"
+	"github.com/insan1a/exile/internal/storage/cache"
 	"github.com/insan1a/exile/internal/storage/person"
 	"github.com/insan1a/exile/internal/storage/person/pg"
-	"golang.org/x/sync/errgroup"
+	"github.com/insan1a/exile/internal/storage/request"
+	requestpg "github.com/insan1a/exile/internal/storage/request/pg"
 )
 
 var (
 	ErrMessageFromat     = errors.New("the message have invalid format")
 	ErrMessageValidation = errors.New("the message is invalid")
-	ErrNilClientFetcher  = errors.New("the client fetcher is nil")
+	ErrNilEnricher       = errors.New("the enricher is nil")
+	ErrReport            = errors.New("failed to report the result of the request")
+	ErrBatchSize         = errors.New("the batch size must be positive")
+	ErrPostponed         = errors.New("the message is postponed until the rate limit resets")
+	ErrPaused            = errors.New("the consuming is paused until the rate limit resets")
+	ErrRetryScheduled    = errors.New("the message is sent to the retry topic")
+	ErrRetryNotScheduled = errors.New("the message is not sent to the retry topic and is consumed again")
+	ErrRetriesExhausted  = errors.New("the retries of the message are exhausted")
+	ErrUnknownPolicy     = errors.New("the enrichment policy is unknown")
+)
+
+// Policy defines how the failure of the provider affects the person.
+type Policy string
+
+const (
+	// PolicyRequired drops the person if the provider fails.
+	PolicyRequired Policy = "required"
+	// PolicyOptional stores the person without the fields of the failed provider,
+	// records the failure and enriches the person again by Reenrich.
+	PolicyOptional Policy = "optional"
+	// PolicyBestEffort stores the person without the fields of the failed provider
+	// and only records the failure.
+	PolicyBestEffort Policy = "best-effort"
+)
+
+// enrichTimeout is the deadline of the enrichment of the person or the batch.
+const enrichTimeout = 2 * time.Second
+
+// EnrichmentError is returned when the person is not fully enriched.
+//
+// The Person holds the fields filled by the succeeded providers,
+// if the deadline is exceeded the error wraps the client.TimeoutError.
+type EnrichmentError struct {
+	Person *models.Person
+	Err    error
+}
+
+// Error returns the errors of the failed providers.
+func (e *EnrichmentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the errors of the failed providers.
+func (e *EnrichmentError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	// AttemptHeader is the header counting the deliveries of the message to the retry topic.
+	AttemptHeader = "x-attempt"
+	// NotBeforeHeader is the header holding the unix time in milliseconds
+	// before which the retried message is not processed.
+	NotBeforeHeader = "x-not-before"
 )
 
 type Option func(c *Service) error
@@ -55,83 +113,187 @@ func WithProducer(producer broker.Producer, topic string) Option {
 	}
 }
 
-func WithPeopleStorage(people person.Storage) Option {
+// WithBatch sets the maximum size of the batch consumed by SaveBatch
+// and the maximum time of waiting for it to be filled.
+func WithBatch(size int, wait time.Duration) Option {
 	return func(s *Service) error {
-		if people == nil {
-			return service.ErrNilPeopleStorage
+		if size < 1 {
+			return ErrBatchSize
 		}
 
-		s.people = people
+		s.batchSize = size
+		s.batchWait = wait
 		return nil
 	}
 }
 
-func WithPostgresPeopleStorage(url string) Option {
+// WithRetry sets the policy of retrying the transient enrichment failures in-process.
+func WithRetry(policy retry.Policy) Option {
+	return func(s *Service) error {
+		s.retry = policy
+		return nil
+	}
+}
+
+// WithRetryProducer injects the producer of the retry topic
+// receiving the messages still failed by the transient errors after the in-process retries.
+//
+// The attempts of the policy limit the deliveries to the retry topic
+// and its delays hold the delivered messages back.
+func WithRetryProducer(producer broker.Producer, policy retry.Policy) Option {
+	return func(s *Service) error {
+		if producer == nil {
+			return service.ErrNilProducer
+		}
+
+		s.retryProducer = producer
+		s.retryTopicPolicy = policy
+		return nil
+	}
+}
+
+// WithPolicies sets the policies of the providers by their names,
+// the providers without the policy are required.
+func WithPolicies(policies map[string]Policy) Option {
+	return func(s *Service) error {
+		for provider, policy := range policies {
+			switch policy {
+			case PolicyRequired, PolicyOptional, PolicyBestEffort:
+			default:
+				return fmt.Errorf("%w: %s: %s", ErrUnknownPolicy, provider, policy)
+			}
+		}
+
+		s.policies = policies
+		return nil
+	}
+}
+
+// WithReplyProducer injects the producer used to reply to the requests
+// with the result of processing.
+func WithReplyProducer(producer broker.Producer) Option {
+	return func(s *Service) error {
+		if producer == nil {
+			return service.ErrNilProducer
+		}
+
+		s.replies = producer
+		return nil
+	}
+}
+
+// WithRequestStorage injects the storage tracking the state of the requests.
+func WithRequestStorage(requests request.Storage) Option {
+	return func(s *Service) error {
+		if requests == nil {
+			return service.ErrNilRequestStorage
+		}
+
+		s.requests = requests
+		return nil
+	}
+}
+
+// WithPostgresRequestStorage injects postgres request storage.
+func WithPostgresRequestStorage(url string) Option {
 	return func(s *Service) error {
 		db, err := storage.NewPostgresPool(url)
 		if err != nil {
 			return err
 		}
 
-		storage, err := pg.New(db)
+		requests, err := requestpg.New(db)
 		if err != nil {
 			return err
 		}
 
-		s.people = storage
-		return nil
+		return WithRequestStorage(requests)(s)
 	}
 }
 
-func WithAgifyClient(fetcher client.Fetcher) Option {
+func WithPeopleStorage(people person.Storage) Option {
 	return func(s *Service) error {
-		if fetcher == nil {
-			return ErrNilClientFetcher
+		if people == nil {
+			return service.ErrNilPeopleStorage
 		}
 
-		s.agify = fetcher
+		s.people = people
 		return nil
 	}
 }
 
-func WithGenderizeClient(fetcher client.Fetcher) Option {
+func WithPostgresPeopleStorage(url string) Option {
 	return func(s *Service) error {
-		if fetcher == nil {
-			return ErrNilClientFetcher
+		db, err := storage.NewPostgresPool(url)
+		if err != nil {
+			return err
 		}
 
-		s.genderize = fetcher
+		storage, err := pg.New(db)
+		if err != nil {
+			return err
+		}
+
+		s.people = storage
+		return nil
+	}
+}
+
+// WithPeopleCache injects the cache of the people shared with the API,
+// the cached pages and statistics are invalidated after the people are stored.
+func WithPeopleCache(c cache.Cache) Option {
+	return func(s *Service) error {
+		s.generation = cache.NewGeneration(c, models.PeopleGeneration)
 		return nil
 	}
 }
 
-func WithNationalizeClient(fetcher client.Fetcher) Option {
+// WithEnricher injects the enricher filling the person fields,
+// usually the client.Registry of the configured providers.
+func WithEnricher(enricher client.Enricher) Option {
 	return func(s *Service) error {
-		if fetcher == nil {
-			return ErrNilClientFetcher
+		if enricher == nil {
+			return ErrNilEnricher
 		}
 
-		s.nationalize = fetcher
+		s.enricher = enricher
 		return nil
 	}
 }
 
 type Service struct {
-	timeout time.Duration
+	timeout   time.Duration
+	batchSize int
+	batchWait time.Duration
+
+	// pending holds the postponed messages processed before the consumed ones.
+	pending  []*broker.Message
+	paused   bool
+	resumeAt time.Time
+
+	retry            retry.Policy
+	retryProducer    broker.Producer
+	retryTopicPolicy retry.Policy
+	// delayedUntil is the time the earliest retried message consumed before its time is due,
+	// the partitions of such messages are rewound and paused until then.
+	delayedUntil time.Time
 
 	consumer      broker.Consumer
 	producer      broker.Producer
 	producerTopic string
+	replies       broker.Producer
 
-	agify       client.Fetcher
-	genderize   client.Fetcher
-	nationalize client.Fetcher
+	enricher client.Enricher
+	policies map[string]Policy
 
-	people person.Storage
+	people   person.Storage
+	requests request.Storage
+
+	generation *cache.Generation
 }
 
 func New(options ...Option) (*Service, error) {
-	c := &Service{}
+	c := &Service{batchSize: 1}
 
 	for _, option := range options {
 		if err := option(c); err != nil {
@@ -142,66 +304,463 @@ func New(options ...Option) (*Service, error) {
 	return c, nil
 }
 
-func (s *Service) Save(ctx context.Context) ([]byte, error) {
+// Save consumes the message, enriches the person and stores it.
+//
+// On success the returned message holds the stored person and keeps
+// the key of the consumed message, otherwise the consumed message is returned.
+// If the enrichment fails the EnrichmentError holding the partially enriched person is returned.
+// If the reply producer is set, the result is sent to the reply topic.
+// The consumed message is committed once it is processed.
+func (s *Service) Save(ctx context.Context) (*broker.Message, error) {
 	msg, err := s.consumer.Consume(s.timeout)
 	if err != nil {
 		return nil, err
 	}
 
-	var p models.Person
-	if err = json.Unmarshal(msg, &p); err != nil {
-		return msg, errors.Join(err, ErrMessageFromat)
+	res, err := s.save(ctx, msg)
+	if errors.Is(err, ErrRetryNotScheduled) {
+		// the message is not committed and is consumed again
+		return res, errors.Join(err, s.consumer.Rewind(msg), s.consumer.Resume())
 	}
 
-	if err = validator.ValidateStruct(p); err != nil {
-		return msg, errors.Join(err, ErrMessageValidation)
+	if commitErr := s.consumer.Commit(msg); commitErr != nil {
+		return res, errors.Join(err, commitErr)
 	}
 
-	clientsCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+	return res, err
+}
 
-	errs, _ := errgroup.WithContext(clientsCtx)
-	errs.Go(func() error {
-		data, err := s.nationalize.Fetch(p.Name)
-		if err != nil {
-			return err
-		}
+// save enriches the person of the message and stores it.
+func (s *Service) save(ctx context.Context, msg *broker.Message) (*broker.Message, error) {
+	p, err := decode(msg)
+	if err != nil {
+		return msg, err
+	}
+
+	err = retry.Do(ctx, s.retry, isRetryable, func() error {
+		clientsCtx, cancel := context.WithTimeout(ctx, enrichTimeout)
+		defer cancel()
 
-		return json.Unmarshal(data, &p)
+		return s.enricher.Enrich(clientsCtx, p)
 	})
-	errs.Go(func() error {
-		data, err := s.agify.Fetch(p.Name)
+	if err = s.applyPolicies(p, err); err != nil {
+		return msg, s.retryLater(msg, &EnrichmentError{Person: p, Err: err})
+	}
+
+	res, err := s.store(ctx, msg, p)
+	if stored(err) {
+		s.invalidatePeople(ctx)
+	}
+
+	return res, err
+}
+
+// Result is the result of processing of the consumed message.
+type Result struct {
+	// Message holds the stored person on success, otherwise the consumed message.
+	Message *broker.Message
+	Err     error
+}
+
+// SaveBatch consumes up to the batch size messages waiting for them
+// no longer than the batch wait, enriches the people at once and stores them.
+//
+// The results are returned in the order of the consumed messages.
+// If no message is consumed the error of the consumer is returned,
+// otherwise the error only stops the batch and is dropped,
+// the persistent failure is returned by the next call consuming nothing.
+//
+// The messages failed by the rate limit of the provider are postponed
+// and the consumer is paused until the limit resets, meanwhile ErrPaused is returned.
+// The messages not delivered to the retry topic are postponed the same way for the first retry delay.
+// The processed messages are committed, the postponed ones are committed once processed,
+// so they are consumed again after the restart.
+// The cached pages and statistics are invalidated once if any person of the batch is stored.
+func (s *Service) SaveBatch(ctx context.Context) ([]Result, error) {
+	if s.paused {
+		if err := s.waitResume(); err != nil {
+			return nil, err
+		}
+	}
+
+	msgs, err := s.consumeBatch()
+	if len(msgs) == 0 {
+		return nil, err
+	}
+
+	results := make([]Result, len(msgs))
+	people := make([]*models.Person, 0, len(msgs))
+	indexes := make([]int, 0, len(msgs))
+	processed := make([]*broker.Message, 0, len(msgs))
+
+	for i, msg := range msgs {
+		results[i].Message = msg
+
+		p, err := decode(msg)
 		if err != nil {
-			return err
+			results[i].Err = err
+			processed = append(processed, msg)
+			continue
 		}
 
-		return json.Unmarshal(data, &p)
-	})
-	errs.Go(func() error {
-		data, err := s.genderize.Fetch(p.Name)
+		people = append(people, p)
+		indexes = append(indexes, i)
+	}
+
+	if len(people) == 0 {
+		return results, s.commit(processed)
+	}
+
+	var retryAfter time.Duration
+	var storedAny bool
+
+	errs := s.enrich(ctx, people)
+	for j, p := range people {
+		i := indexes[j]
+		errs[j] = s.applyPolicies(p, errs[j])
+		if wait, ok := client.RetryAfter(errs[j]); ok {
+			s.pending = append(s.pending, msgs[i])
+			retryAfter = max(retryAfter, wait)
+			results[i].Err = errors.Join(errs[j], ErrPostponed)
+			continue
+		}
+
+		if errs[j] != nil {
+			results[i].Err = s.retryLater(msgs[i], &EnrichmentError{Person: p, Err: errs[j]})
+			if errors.Is(results[i].Err, ErrRetryNotScheduled) {
+				s.pending = append(s.pending, msgs[i])
+				retryAfter = max(retryAfter, s.retryTopicPolicy.Delay(1))
+				continue
+			}
+			processed = append(processed, msgs[i])
+			continue
+		}
+		processed = append(processed, msgs[i])
+
+		res, err := s.store(ctx, msgs[i], p)
+		results[i] = Result{Message: res, Err: err}
+		storedAny = storedAny || stored(err)
+	}
+
+	if storedAny {
+		s.invalidatePeople(ctx)
+	}
+
+	err = s.commit(processed)
+	if retryAfter > 0 {
+		return results, errors.Join(err, s.pause(retryAfter))
+	}
+
+	return results, err
+}
+
+// commit commits the processed messages.
+func (s *Service) commit(msgs []*broker.Message) error {
+	var errs []error
+	for _, msg := range msgs {
+		if err := s.consumer.Commit(msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// enrich enriches the people retrying the transient failures in-process
+// by the retry policy, the rate limited people are not retried.
+func (s *Service) enrich(ctx context.Context, people []*models.Person) []error {
+	errs := make([]error, len(people))
+
+	indexes := make([]int, len(people))
+	for i := range people {
+		indexes[i] = i
+	}
+
+	for attempt := 1; ; attempt++ {
+		batch := make([]*models.Person, len(indexes))
+		for j, i := range indexes {
+			batch[j] = people[i]
+		}
+
+		clientsCtx, cancel := context.WithTimeout(ctx, enrichTimeout)
+		batchErrs := client.EnrichBatch(clientsCtx, s.enricher, batch)
+		cancel()
+
+		failed := make([]int, 0, len(indexes))
+		for j, i := range indexes {
+			errs[i] = batchErrs[j]
+			if isRetryable(batchErrs[j]) {
+				failed = append(failed, i)
+			}
+		}
+
+		if len(failed) == 0 || attempt >= s.retry.Attempts {
+			return errs
+		}
+
+		if err := s.retry.Wait(ctx, attempt); err != nil {
+			return errs
+		}
+
+		indexes = failed
+	}
+}
+
+// applyPolicies records the failures of the non-required providers
+// in the enrichment status of the person and returns the failures of the required ones.
+//
+// The error not caused by the providers is returned as is.
+func (s *Service) applyPolicies(p *models.Person, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	providerErrs := client.ProviderErrors(err)
+	if len(providerErrs) == 0 {
+		return err
+	}
+
+	var required []error
+	status := make(models.EnrichmentStatus, len(providerErrs))
+	for _, pe := range providerErrs {
+		if s.policy(pe.Provider) == PolicyRequired {
+			required = append(required, pe)
+			continue
+		}
+
+		status[pe.Provider] = pe.Err.Error()
+	}
+
+	if len(required) > 0 {
+		return errors.Join(required...)
+	}
+
+	p.EnrichmentStatus = status
+	return nil
+}
+
+// policy returns the policy of the provider.
+func (s *Service) policy(provider string) Policy {
+	if policy, ok := s.policies[provider]; ok {
+		return policy
+	}
+
+	return PolicyRequired
+}
+
+// invalidatePeople bumps the generation of the cached pages and statistics,
+// the cache failures are ignored as they expire anyway.
+func (s *Service) invalidatePeople(ctx context.Context) {
+	if s.generation != nil {
+		_ = s.generation.Bump(ctx)
+	}
+}
+
+// isRetryable reports whether the enrichment is worth retrying in-process.
+func isRetryable(err error) bool {
+	return client.IsTransient(err) && !errors.Is(err, client.ErrRateLimited)
+}
+
+// retryLater sends the message failed by the transient error to the retry topic
+// with the incremented attempt and the time before which it is not processed.
+//
+// If the error is not transient or the retry producer is not set the error is returned as is,
+// if the attempts are exhausted it is joined with ErrRetriesExhausted
+// and if the message is not delivered to the retry topic it is joined with ErrRetryNotScheduled.
+func (s *Service) retryLater(msg *broker.Message, err error) error {
+	if s.retryProducer == nil || !client.IsTransient(err) {
+		return err
+	}
+
+	attempt, _ := strconv.Atoi(msg.Headers[AttemptHeader])
+	if attempt >= s.retryTopicPolicy.Attempts {
+		return errors.Join(err, ErrRetriesExhausted)
+	}
+
+	headers := make(map[string]string, len(msg.Headers)+2)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[AttemptHeader] = strconv.Itoa(attempt + 1)
+	headers[NotBeforeHeader] = strconv.FormatInt(time.Now().Add(s.retryTopicPolicy.Delay(attempt+1)).UnixMilli(), 10)
+
+	if produceErr := s.retryProducer.ProduceMessage(&broker.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}); produceErr != nil {
+		return errors.Join(err, produceErr, ErrRetryNotScheduled)
+	}
+
+	return errors.Join(err, ErrRetryScheduled)
+}
+
+// pause pauses the consumer for the given time.
+func (s *Service) pause(wait time.Duration) error {
+	s.resumeAt = time.Now().Add(wait)
+	if s.paused {
+		return nil
+	}
+
+	if err := s.consumer.Pause(); err != nil {
+		return err
+	}
+
+	s.paused = true
+	return nil
+}
+
+// waitResume polls the paused consumer to stay in the group
+// until the rate limit resets and then resumes it.
+//
+// While the rate limit is not reset ErrPaused is returned.
+func (s *Service) waitResume() error {
+	if wait := time.Until(s.resumeAt); wait > 0 {
+		if msg, err := s.consumer.Consume(min(s.timeout, wait)); err == nil {
+			s.pending = append(s.pending, msg)
+		}
+
+		return ErrPaused
+	}
+
+	if err := s.consumer.Resume(); err != nil {
+		return err
+	}
+
+	s.paused = false
+	return nil
+}
+
+// consumeBatch takes the postponed messages and consumes the new ones
+// until the batch is full or the batch wait is over.
+//
+// The retried messages consumed before their time are not kept,
+// their partitions are rewound to them and paused until they are due.
+func (s *Service) consumeBatch() ([]*broker.Message, error) {
+	msgs := make([]*broker.Message, 0, s.batchSize)
+	deadline := time.Now().Add(s.batchWait)
+
+	if err := s.resumeDelayed(); err != nil {
+		return nil, err
+	}
+
+	n := min(len(s.pending), s.batchSize)
+	msgs = append(msgs, s.pending[:n]...)
+	s.pending = s.pending[n:]
+
+	for len(msgs) < s.batchSize {
+		timeout := s.timeout
+		if len(msgs) > 0 {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				break
+			}
+			timeout = min(timeout, remaining)
+		}
+
+		msg, err := s.consumer.Consume(timeout)
 		if err != nil {
-			return err
+			return msgs, err
 		}
 
-		return json.Unmarshal(data, &p)
-	})
+		if at := notBefore(msg); at.After(time.Now()) {
+			if err = s.delay(msg, at); err != nil {
+				return msgs, err
+			}
+			continue
+		}
 
-	if err := errs.Wait(); err != nil {
-		return msg, err
+		if len(msgs) == 0 {
+			deadline = time.Now().Add(s.batchWait)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// delay rewinds the partition of the retried message consumed before its time.
+func (s *Service) delay(msg *broker.Message, at time.Time) error {
+	if err := s.consumer.Rewind(msg); err != nil {
+		return err
+	}
+
+	if s.delayedUntil.IsZero() || at.Before(s.delayedUntil) {
+		s.delayedUntil = at
+	}
+
+	return nil
+}
+
+// resumeDelayed resumes the rewound partitions once the earliest retried message is due,
+// the partitions of the messages still not due are rewound again on consumption.
+func (s *Service) resumeDelayed() error {
+	if s.delayedUntil.IsZero() || s.paused || time.Now().Before(s.delayedUntil) {
+		return nil
 	}
 
-	err = s.people.Create(ctx, &p)
+	if err := s.consumer.Resume(); err != nil {
+		return err
+	}
+
+	s.delayedUntil = time.Time{}
+	return nil
+}
+
+// notBefore returns the time before which the retried message is not processed.
+func notBefore(msg *broker.Message) time.Time {
+	ms, err := strconv.ParseInt(msg.Headers[NotBeforeHeader], 10, 64)
 	if err != nil {
-		return nil, err
+		return time.Time{}
+	}
+
+	return time.UnixMilli(ms)
+}
+
+// decode returns the valid person from the message.
+func decode(msg *broker.Message) (*models.Person, error) {
+	var p models.Person
+	if err := json.Unmarshal(msg.Value, &p); err != nil {
+		return nil, errors.Join(err, ErrMessageFromat)
+	}
+	p.Normalize()
+
+	if err := validator.ValidateStruct(p); err != nil {
+		return nil, errors.Join(err, ErrMessageValidation)
+	}
+
+	return &p, nil
+}
+
+// store stores the enriched person and reports the result.
+func (s *Service) store(ctx context.Context, msg *broker.Message, p *models.Person) (*broker.Message, error) {
+	if err := s.people.Create(ctx, p); err != nil {
+		return msg, err
+	}
+
+	result, _ := json.Marshal(p)
+	res := &broker.Message{Key: msg.Key, Value: result, Headers: msg.Headers}
+
+	if err := s.report(ctx, msg.Key, models.ReplyMessage{
+		Status: models.RequestStatusStored,
+		Person: p,
+	}); err != nil {
+		return res, errors.Join(err, ErrReport)
 	}
 
-	result, _ := json.Marshal(&p)
-	return result, nil
+	return res, nil
+}
+
+// stored reports whether the person is stored when store returns the error.
+func stored(err error) bool {
+	return err == nil || errors.Is(err, ErrReport)
 }
 
-func (s *Service) SendErrMessage(data []byte, err string) error {
+// SendErrMessage sends the message with the error to the failure topic
+// and reports the failed status of the request.
+func (s *Service) SendErrMessage(ctx context.Context, msg *broker.Message, err string) error {
 	meta := make(map[string]any)
-	if err := json.Unmarshal(data, &meta); err != nil {
+	if err := json.Unmarshal(msg.Value, &meta); err != nil {
 		return err
 	}
 
@@ -210,5 +769,50 @@ func (s *Service) SendErrMessage(data []byte, err string) error {
 		Error: err,
 	})
 
-	return s.producer.Produce(errMsg)
+	if err := s.producer.ProduceMessage(&broker.Message{Key: msg.Key, Value: errMsg}); err != nil {
+		return err
+	}
+
+	if err := s.report(ctx, msg.Key, models.ReplyMessage{
+		Status: models.RequestStatusFailed,
+		Error:  err,
+	}); err != nil {
+		return errors.Join(err, ErrReport)
+	}
+
+	return nil
+}
+
+// report updates the state of the request with the given ID
+// and sends the reply to the request.
+//
+// The request is tracked only if the request storage is set and the ID is UUID.
+// The reply is sent only if the reply producer is set and the ID is not empty.
+func (s *Service) report(ctx context.Context, id string, reply models.ReplyMessage) error {
+	if _, err := uuid.Parse(id); err == nil && s.requests != nil {
+		r := models.Request{
+			ID:     id,
+			Status: reply.Status,
+			Error:  reply.Error,
+		}
+		if reply.Person != nil {
+			r.PersonID = reply.Person.ID
+		}
+
+		if err = s.requests.Update(ctx, &r); err != nil {
+			return err
+		}
+	}
+
+	if s.replies == nil || id == "" {
+		return nil
+	}
+
+	reply.RequestID = id
+	data, err := json.Marshal(&reply)
+	if err != nil {
+		return err
+	}
+
+	return s.replies.ProduceMessage(&broker.Message{Key: id, Value: data})
 }
@@ -3,13 +3,23 @@ package person
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/insan1a/exile/internal/client"
 	clientmocks "github.com/insan1a/exile/internal/client/mocks"
+	"github.com/insan1a/exile/internal/lib/retry"
+	"github.com/insan1a/exile/internal/lib/stubapi"
 	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/storage/broker"
 	brokermocks "github.com/insan1a/exile/internal/storage/broker/mocks"
+	cachemocks "github.com/insan1a/exile/internal/storage/cache/mocks"
 	storagemocks "github.com/insan1a/exile/internal/storage/person/mocks"
+	requestmocks "github.com/insan1a/exile/internal/storage/request/mocks"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNew(t *testing.T) {
@@ -56,6 +66,21 @@ func TestNew(t *testing.T) {
 			args:    args{[]Option{WithPeopleStorage(storagemocks.NewStorage(t))}},
 			wantErr: false,
 		},
+		{
+			name:    "service with nil enricher",
+			args:    args{[]Option{WithEnricher(nil)}},
+			wantErr: true,
+		},
+		{
+			name:    "service with unknown policy",
+			args:    args{[]Option{WithPolicies(map[string]Policy{"agify": "sometimes"})}},
+			wantErr: true,
+		},
+		{
+			name:    "service with enricher",
+			args:    args{[]Option{WithEnricher(clientmocks.NewEnricher(t))}},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -70,6 +95,44 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func newRegistry(t *testing.T, agify, genderize, nationalize client.Fetcher) *client.Registry {
+	t.Helper()
+
+	registry, err := client.NewRegistry(
+		client.NewFetcherEnricher(client.ProviderAgify, agify, client.FieldAge),
+		client.NewFetcherEnricher(client.ProviderGenderize, genderize, client.FieldGender),
+		client.NewFetcherEnricher(client.ProviderNationalize, nationalize, client.FieldNationality),
+	)
+	if err != nil {
+		t.Fatalf("client.NewRegistry() error = %v", err)
+	}
+
+	return registry
+}
+
+// enriched matches the person enriched by the registry of newRegistry,
+// the provenance is checked to hold the providers of the fields without the times.
+func enriched(want models.Person) any {
+	providers := map[string]string{
+		string(client.FieldAge):         client.ProviderAgify,
+		string(client.FieldGender):      client.ProviderGenderize,
+		string(client.FieldNationality): client.ProviderNationalize,
+	}
+
+	return mock.MatchedBy(func(p *models.Person) bool {
+		for f, source := range p.Provenance {
+			if source.Source != providers[f] || source.UpdatedAt.IsZero() {
+				return false
+			}
+		}
+
+		got := *p
+		got.Provenance = nil
+
+		return reflect.DeepEqual(got, want)
+	})
+}
+
 func TestService_Save(t *testing.T) {
 	consumer := brokermocks.NewConsumer(t)
 	storage := storagemocks.NewStorage(t)
@@ -82,9 +145,7 @@ func TestService_Save(t *testing.T) {
 		WithConsumer(consumer),
 		WithPeopleStorage(storage),
 		WithTimeout(timeout),
-		WithNationalizeClient(nationalize),
-		WithAgifyClient(agify),
-		WithGenderizeClient(genderize),
+		WithEnricher(newRegistry(t, agify, genderize, nationalize)),
 	)
 	if err != nil {
 		t.Fatalf("New() error = %v", err)
@@ -102,15 +163,16 @@ func TestService_Save(t *testing.T) {
 
 	consumer.On("Consume", timeout).
 		Once().
-		Return(data, nil)
-	agify.On("Fetch", tp.Name).Once().Return([]byte(`{"age":25}`), nil)
-	genderize.On("Fetch", tp.Name).Once().Return([]byte(`{"gender":"male"}`), nil)
-	nationalize.On("Fetch", tp.Name).Once().Return([]byte(`{"nationality":"US"}`), nil)
+		Return(&broker.Message{Value: data}, nil)
+	consumer.On("Commit", mock.Anything).Once().Return(nil)
+	agify.On("Fetch", mock.Anything, tp.Name).Once().Return([]byte(`{"age":25}`), nil)
+	genderize.On("Fetch", mock.Anything, tp.Name).Once().Return([]byte(`{"gender":"male"}`), nil)
+	nationalize.On("Fetch", mock.Anything, tp.Name).Once().Return([]byte(`{"nationality":"US"}`), nil)
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	storage.On("Create", ctx, &tp).
+	storage.On("Create", ctx, enriched(tp)).
 		Once().
 		Return(nil)
 
@@ -120,6 +182,404 @@ func TestService_Save(t *testing.T) {
 	}
 }
 
+func TestService_SaveTimeout(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+	nationalize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithTimeout(time.Millisecond),
+		WithEnricher(newRegistry(t, agify, genderize, nationalize)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data, _ := json.Marshal(&models.Person{Name: "Roman", Surname: "Kravchuk"})
+
+	consumer.On("Consume", time.Millisecond).Once().Return(&broker.Message{Value: data}, nil)
+	consumer.On("Commit", mock.Anything).Once().Return(nil)
+	agify.On("Fetch", mock.Anything, "Roman").Once().Return(func(ctx context.Context, _ string) ([]byte, error) {
+		<-ctx.Done()
+		return nil, &client.TimeoutError{API: "agify", Err: ctx.Err()}
+	})
+	genderize.On("Fetch", mock.Anything, "Roman").Once().Return([]byte(`{"gender":"male"}`), nil)
+	nationalize.On("Fetch", mock.Anything, "Roman").Once().Return([]byte(`{"nationality":"US"}`), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = svc.Save(ctx)
+
+	var timeoutErr *client.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("svc.Save() error = %v, want the timeout error", err)
+	}
+
+	var enrichmentErr *EnrichmentError
+	if !errors.As(err, &enrichmentErr) {
+		t.Fatalf("svc.Save() error = %v, want the enrichment error", err)
+	}
+	if enrichmentErr.Person.Gender != "male" || enrichmentErr.Person.Nationality != "US" {
+		t.Errorf("svc.Save() person = %v, want the partial result", enrichmentErr.Person)
+	}
+}
+
+func TestService_SavePartial(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+	storage := storagemocks.NewStorage(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+	nationalize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithPeopleStorage(storage),
+		WithTimeout(time.Millisecond),
+		WithPolicies(map[string]Policy{
+			client.ProviderGenderize:   PolicyBestEffort,
+			client.ProviderNationalize: PolicyOptional,
+		}),
+		WithEnricher(newRegistry(t, agify, genderize, nationalize)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data, _ := json.Marshal(&models.Person{Name: "Roman", Surname: "Kravchuk"})
+	consumer.On("Consume", time.Millisecond).Twice().Return(&broker.Message{Value: data}, nil)
+	consumer.On("Commit", mock.Anything).Twice().Return(nil)
+	genderize.On("Fetch", mock.Anything, "Roman").Twice().Return([]byte(`{"gender":"male"}`), nil)
+	nationalize.On("Fetch", mock.Anything, "Roman").Twice().Return(nil, client.ErrFindNationality)
+
+	agify.On("Fetch", mock.Anything, "Roman").Once().Return([]byte(`{"age":25}`), nil)
+	storage.On("Create", context.Background(), enriched(models.Person{
+		Name:             "Roman",
+		Surname:          "Kravchuk",
+		Age:              25,
+		Gender:           "male",
+		EnrichmentStatus: models.EnrichmentStatus{client.ProviderNationalize: client.ErrFindNationality.Error()},
+	})).Once().Return(nil)
+
+	if _, err = svc.Save(context.Background()); err != nil {
+		t.Fatalf("svc.Save() error = %v", err)
+	}
+
+	agify.On("Fetch", mock.Anything, "Roman").Once().Return(nil, client.ErrFindAge)
+
+	if _, err = svc.Save(context.Background()); !errors.Is(err, client.ErrFindAge) {
+		t.Errorf("svc.Save() error = %v, want %v", err, client.ErrFindAge)
+	}
+}
+
+func TestService_SaveBatch(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+	storage := storagemocks.NewStorage(t)
+	cache := cachemocks.NewCache(t)
+	agify := clientmocks.NewBatchFetcher(t)
+	genderize := clientmocks.NewBatchFetcher(t)
+	nationalize := clientmocks.NewBatchFetcher(t)
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithPeopleStorage(storage),
+		WithTimeout(time.Second),
+		WithBatch(3, time.Second),
+		WithEnricher(newRegistry(t, agify, genderize, nationalize)),
+		WithPeopleCache(cache),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	roman := models.Person{Name: "Roman", Surname: "Kravchuk", Age: 25, Gender: "male", Nationality: "US"}
+	olga := models.Person{Name: "Olga", Surname: "Petrova", Age: 30, Gender: "female", Nationality: "RU"}
+
+	romanData, _ := json.Marshal(&models.Person{Name: roman.Name, Surname: roman.Surname})
+	olgaData, _ := json.Marshal(&models.Person{Name: olga.Name, Surname: olga.Surname})
+
+	consumer.On("Consume", mock.Anything).Once().Return(&broker.Message{Key: "1", Value: romanData}, nil)
+	consumer.On("Consume", mock.Anything).Once().Return(&broker.Message{Key: "2", Value: []byte(`{}`)}, nil)
+	consumer.On("Consume", mock.Anything).Once().Return(&broker.Message{Key: "3", Value: olgaData}, nil)
+	consumer.On("Commit", mock.Anything).Times(3).Return(nil)
+
+	names := []string{roman.Name, olga.Name}
+	agify.On("FetchBatch", mock.Anything, names).Once().
+		Return([]client.Result{{Data: []byte(`{"age":25}`)}, {Data: []byte(`{"age":30}`)}}, nil)
+	genderize.On("FetchBatch", mock.Anything, names).Once().
+		Return([]client.Result{{Data: []byte(`{"gender":"male"}`)}, {Data: []byte(`{"gender":"female"}`)}}, nil)
+	nationalize.On("FetchBatch", mock.Anything, names).Once().
+		Return([]client.Result{{Data: []byte(`{"nationality":"US"}`)}, {Data: []byte(`{"nationality":"RU"}`)}}, nil)
+
+	storage.On("Create", context.Background(), enriched(roman)).Once().Return(nil)
+	storage.On("Create", context.Background(), enriched(olga)).Once().Return(nil)
+	cache.On("Set", context.Background(), models.PeopleGeneration, mock.Anything, time.Duration(0)).
+		Once().Return(nil)
+
+	results, err := svc.SaveBatch(context.Background())
+	if err != nil {
+		t.Fatalf("svc.SaveBatch() error = %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("svc.SaveBatch() returned %d results, want 3", len(results))
+	}
+	for i, key := range []string{"1", "2", "3"} {
+		if results[i].Message.Key != key {
+			t.Errorf("svc.SaveBatch() key = %v, want %v", results[i].Message.Key, key)
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("svc.SaveBatch() errors = %v, %v", results[0].Err, results[2].Err)
+	}
+	if !errors.Is(results[1].Err, ErrMessageValidation) {
+		t.Errorf("svc.SaveBatch() error = %v, want %v", results[1].Err, ErrMessageValidation)
+	}
+}
+
+func TestService_SaveBatchStubAPI(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+	storage := storagemocks.NewStorage(t)
+
+	_, baseURL := stubapi.Start(t, stubapi.WithNames(map[string]stubapi.Entry{
+		"Roman": {Age: 25, Gender: "male", Countries: []stubapi.Country{{ID: "US", Probability: 0.5}}},
+	}))
+
+	enrichers, err := client.Select(client.Providers(
+		client.WithAPI(client.ProviderAgify, client.WithBaseURL(baseURL+stubapi.PathAgify)),
+		client.WithAPI(client.ProviderGenderize, client.WithBaseURL(baseURL+stubapi.PathGenderize)),
+		client.WithAPI(client.ProviderNationalize, client.WithBaseURL(baseURL+stubapi.PathNationalize)),
+	), []string{client.ProviderAgify, client.ProviderGenderize, client.ProviderNationalize})
+	if err != nil {
+		t.Fatalf("client.Select() error = %v", err)
+	}
+
+	registry, err := client.NewRegistry(enrichers...)
+	if err != nil {
+		t.Fatalf("client.NewRegistry() error = %v", err)
+	}
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithPeopleStorage(storage),
+		WithTimeout(time.Second),
+		WithBatch(2, time.Second),
+		WithEnricher(registry),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	consumer.On("Consume", mock.Anything).Once().
+		Return(&broker.Message{Key: "1", Value: []byte(`{"name":"Roman","surname":"Kravchuk"}`)}, nil)
+	consumer.On("Consume", mock.Anything).Once().
+		Return(&broker.Message{Key: "2", Value: []byte(`{"name":"Xyzzy","surname":"Kravchuk"}`)}, nil)
+	consumer.On("Commit", mock.Anything).Twice().Return(nil)
+
+	storage.On("Create", context.Background(), mock.MatchedBy(func(p *models.Person) bool {
+		return p.Name == "Roman" && p.Age == 25 && p.Gender == "male" && p.Nationality == "US"
+	})).Once().Return(nil)
+
+	results, err := svc.SaveBatch(context.Background())
+	if err != nil {
+		t.Fatalf("svc.SaveBatch() error = %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("svc.SaveBatch() returned %d results, want 2", len(results))
+	}
+	if results[0].Err != nil {
+		t.Errorf("svc.SaveBatch() error = %v", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, client.ErrFindAge) {
+		t.Errorf("svc.SaveBatch() error = %v, want %v", results[1].Err, client.ErrFindAge)
+	}
+}
+
+func TestService_SaveBatchRateLimited(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+	agify := clientmocks.NewBatchFetcher(t)
+	genderize := clientmocks.NewBatchFetcher(t)
+	nationalize := clientmocks.NewBatchFetcher(t)
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithTimeout(time.Millisecond),
+		WithBatch(1, 0),
+		WithEnricher(newRegistry(t, agify, genderize, nationalize)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data, _ := json.Marshal(&models.Person{Name: "Roman", Surname: "Kravchuk"})
+	msg := &broker.Message{Key: "1", Value: data}
+
+	rateLimited := &client.RateLimitError{API: "agify", RetryAfter: time.Hour}
+	consumer.On("Consume", time.Millisecond).Once().Return(msg, nil)
+	agify.On("FetchBatch", mock.Anything, []string{"Roman"}).Once().Return(nil, rateLimited)
+	genderize.On("FetchBatch", mock.Anything, []string{"Roman"}).Once().Return([]client.Result{{Data: []byte(`{}`)}}, nil)
+	nationalize.On("FetchBatch", mock.Anything, []string{"Roman"}).Once().Return([]client.Result{{Data: []byte(`{}`)}}, nil)
+	consumer.On("Pause").Once().Return(nil)
+
+	results, err := svc.SaveBatch(context.Background())
+	if err != nil {
+		t.Fatalf("svc.SaveBatch() error = %v", err)
+	}
+	if len(results) != 1 || !errors.Is(results[0].Err, ErrPostponed) {
+		t.Fatalf("svc.SaveBatch() results = %v, want the postponed message", results)
+	}
+
+	consumer.On("Consume", time.Millisecond).Once().Return(nil, errors.New("timed out"))
+
+	if _, err = svc.SaveBatch(context.Background()); !errors.Is(err, ErrPaused) {
+		t.Errorf("svc.SaveBatch() error = %v, want %v", err, ErrPaused)
+	}
+	if len(svc.pending) != 1 || svc.pending[0] != msg {
+		t.Errorf("svc.SaveBatch() did not keep the postponed message")
+	}
+}
+
+func TestService_SaveBatchRetry(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+	retries := brokermocks.NewProducer(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+	nationalize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithTimeout(time.Millisecond),
+		WithBatch(1, 0),
+		WithRetry(retry.Policy{Attempts: 2, Initial: time.Millisecond}),
+		WithRetryProducer(retries, retry.Policy{Attempts: 2, Initial: time.Minute}),
+		WithEnricher(newRegistry(t, agify, genderize, nationalize)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data, _ := json.Marshal(&models.Person{Name: "Roman", Surname: "Kravchuk"})
+	serverErr := client.APIError{Message: "bad gateway", StatusCode: 502}
+
+	consumer.On("Consume", time.Millisecond).Once().
+		Return(&broker.Message{Key: "1", Value: data, Headers: map[string]string{AttemptHeader: "1"}}, nil)
+	consumer.On("Commit", mock.Anything).Twice().Return(nil)
+	agify.On("Fetch", mock.Anything, "Roman").Twice().Return(nil, serverErr)
+	genderize.On("Fetch", mock.Anything, "Roman").Times(4).Return([]byte(`{}`), nil)
+	nationalize.On("Fetch", mock.Anything, "Roman").Times(4).Return([]byte(`{}`), nil)
+	retries.On("ProduceMessage", mock.MatchedBy(func(msg *broker.Message) bool {
+		return msg.Key == "1" && msg.Headers[AttemptHeader] == "2" && notBefore(msg).After(time.Now())
+	})).Once().Return(nil)
+
+	results, err := svc.SaveBatch(context.Background())
+	if err != nil {
+		t.Fatalf("svc.SaveBatch() error = %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrRetryScheduled) {
+		t.Fatalf("svc.SaveBatch() error = %v, want %v", results[0].Err, ErrRetryScheduled)
+	}
+
+	consumer.On("Consume", time.Millisecond).Once().
+		Return(&broker.Message{Key: "1", Value: data, Headers: map[string]string{AttemptHeader: "2"}}, nil)
+	agify.On("Fetch", mock.Anything, "Roman").Twice().Return(nil, serverErr)
+
+	results, err = svc.SaveBatch(context.Background())
+	if err != nil {
+		t.Fatalf("svc.SaveBatch() error = %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrRetriesExhausted) {
+		t.Errorf("svc.SaveBatch() error = %v, want %v", results[0].Err, ErrRetriesExhausted)
+	}
+}
+
+func TestService_SaveBatchRetryNotDelivered(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+	retries := brokermocks.NewProducer(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+	nationalize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithTimeout(time.Millisecond),
+		WithBatch(1, 0),
+		WithRetry(retry.Policy{Attempts: 1}),
+		WithRetryProducer(retries, retry.Policy{Attempts: 2, Initial: time.Minute}),
+		WithEnricher(newRegistry(t, agify, genderize, nationalize)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data, _ := json.Marshal(&models.Person{Name: "Roman", Surname: "Kravchuk"})
+	msg := &broker.Message{Key: "1", Value: data}
+	deliveryErr := errors.New("delivery failed")
+
+	consumer.On("Consume", time.Millisecond).Once().Return(msg, nil)
+	agify.On("Fetch", mock.Anything, "Roman").Once().Return(nil, client.APIError{Message: "bad gateway", StatusCode: 502})
+	genderize.On("Fetch", mock.Anything, "Roman").Once().Return([]byte(`{}`), nil)
+	nationalize.On("Fetch", mock.Anything, "Roman").Once().Return([]byte(`{}`), nil)
+	retries.On("ProduceMessage", mock.Anything).Once().Return(deliveryErr)
+	consumer.On("Pause").Once().Return(nil)
+
+	results, err := svc.SaveBatch(context.Background())
+	if err != nil {
+		t.Fatalf("svc.SaveBatch() error = %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrRetryNotScheduled) || !errors.Is(results[0].Err, deliveryErr) {
+		t.Errorf("svc.SaveBatch() error = %v, want %v", results[0].Err, ErrRetryNotScheduled)
+	}
+	if len(svc.pending) != 1 || svc.pending[0] != msg {
+		t.Errorf("svc.SaveBatch() did not keep the message to consume again")
+	}
+	consumer.AssertNotCalled(t, "Commit", msg)
+}
+
+func TestService_SaveBatchDelayed(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithTimeout(time.Millisecond),
+		WithBatch(1, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	due := time.Now().Add(50 * time.Millisecond)
+	msg := &broker.Message{Key: "1", Value: []byte(`{}`), Headers: map[string]string{
+		AttemptHeader:   "1",
+		NotBeforeHeader: strconv.FormatInt(due.UnixMilli(), 10),
+	}}
+
+	timedOut := errors.New("timed out")
+	consumer.On("Consume", time.Millisecond).Once().Return(msg, nil)
+	consumer.On("Rewind", msg).Once().Return(nil)
+	consumer.On("Consume", time.Millisecond).Once().Return(nil, timedOut)
+
+	if _, err = svc.SaveBatch(context.Background()); !errors.Is(err, timedOut) {
+		t.Fatalf("svc.SaveBatch() error = %v, want %v", err, timedOut)
+	}
+
+	time.Sleep(time.Until(due))
+
+	consumer.On("Resume").Once().Return(nil)
+	consumer.On("Consume", time.Millisecond).Once().Return(msg, nil)
+	consumer.On("Commit", msg).Once().Return(nil)
+
+	results, err := svc.SaveBatch(context.Background())
+	if err != nil {
+		t.Fatalf("svc.SaveBatch() error = %v", err)
+	}
+	if len(results) != 1 || !errors.Is(results[0].Err, ErrMessageValidation) {
+		t.Errorf("svc.SaveBatch() results = %v, want the due message", results)
+	}
+}
+
 func TestService_SendErrMessage(t *testing.T) {
 	producer := brokermocks.NewProducer(t)
 
@@ -142,11 +602,106 @@ func TestService_SendErrMessage(t *testing.T) {
 
 	data, _ := json.Marshal(&msg)
 
-	producer.On("Produce", data).
+	producer.On("ProduceMessage", &broker.Message{Key: "key", Value: data}).
+		Once().
+		Return(nil)
+
+	err = svc.SendErrMessage(context.Background(), &broker.Message{Key: "key", Value: param}, errStr)
+	if err != nil {
+		t.Fatalf("svc.SendErrMessage() error = %v", err)
+	}
+}
+
+func TestService_SaveWithReply(t *testing.T) {
+	consumer := brokermocks.NewConsumer(t)
+	storage := storagemocks.NewStorage(t)
+	replies := brokermocks.NewProducer(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+	nationalize := clientmocks.NewFetcher(t)
+	timeout := time.Second
+
+	svc, err := New(
+		WithConsumer(consumer),
+		WithPeopleStorage(storage),
+		WithReplyProducer(replies),
+		WithTimeout(timeout),
+		WithEnricher(newRegistry(t, agify, genderize, nationalize)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tp := models.Person{
+		Name:        "Roman",
+		Surname:     "Kravchuk",
+		Age:         25,
+		Gender:      "male",
+		Nationality: "US",
+	}
+
+	data, _ := json.Marshal(&tp)
+
+	consumer.On("Consume", timeout).
+		Once().
+		Return(&broker.Message{Key: "request-id", Value: data}, nil)
+	consumer.On("Commit", mock.Anything).Once().Return(nil)
+	agify.On("Fetch", mock.Anything, tp.Name).Once().Return([]byte(`{"age":25}`), nil)
+	genderize.On("Fetch", mock.Anything, tp.Name).Once().Return([]byte(`{"gender":"male"}`), nil)
+	nationalize.On("Fetch", mock.Anything, tp.Name).Once().Return([]byte(`{"nationality":"US"}`), nil)
+	storage.On("Create", context.Background(), enriched(tp)).Once().Return(nil)
+	replies.On("ProduceMessage", mock.MatchedBy(func(msg *broker.Message) bool {
+		var reply models.ReplyMessage
+		if err := json.Unmarshal(msg.Value, &reply); err != nil || reply.Person == nil {
+			return false
+		}
+
+		p := *reply.Person
+		p.Provenance = nil
+
+		return msg.Key == "request-id" && reply.Status == models.RequestStatusStored && reflect.DeepEqual(p, tp)
+	})).
+		Once().
+		Return(nil)
+
+	res, err := svc.Save(context.Background())
+	if err != nil {
+		t.Fatalf("svc.Save() error = %v", err)
+	}
+
+	if res.Key != "request-id" {
+		t.Errorf("svc.Save() key = %v, want %v", res.Key, "request-id")
+	}
+}
+
+func TestService_SendErrMessageWithRequestStorage(t *testing.T) {
+	producer := brokermocks.NewProducer(t)
+	requests := requestmocks.NewStorage(t)
+
+	svc, err := New(
+		WithProducer(producer, ""),
+		WithRequestStorage(requests),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	id := "05dd6483-1938-4d8b-9a45-7f61a69ad377"
+	errStr := "some error"
+
+	producer.On("ProduceMessage", mock.Anything).
+		Once().
+		Return(nil)
+	requests.On("Update", ctx, &models.Request{
+		ID:     id,
+		Status: models.RequestStatusFailed,
+		Error:  errStr,
+	}).
 		Once().
 		Return(nil)
 
-	err = svc.SendErrMessage(param, errStr)
+	err = svc.SendErrMessage(ctx, &broker.Message{Key: id, Value: []byte(`{}`)}, errStr)
 	if err != nil {
 		t.Fatalf("svc.SendErrMessage() error = %v", err)
 	}
@@ -0,0 +1,420 @@
+package reenrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/insan1a/exile/internal/client"
+	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/service"
+	"github.com/insan1a/exile/internal/storage"
+	"github.com/insan1a/exile/internal/storage/cache"
+	"github.com/insan1a/exile/internal/storage/job"
+	jobpg "github.com/insan1a/exile/internal/storage/job/pg"
+	"github.com/insan1a/exile/internal/storage/person"
+	"github.com/insan1a/exile/internal/storage/person/pg"
+)
+
+var (
+	ErrNilEnricher = errors.New("the enricher is nil")
+	ErrBatchSize   = errors.New("the batch size must be positive")
+)
+
+const (
+	// DefaultJob is the name the progress of the pass is stored by.
+	DefaultJob = "reenrich"
+	// DefaultBatchSize is the number of people re-enriched at once.
+	DefaultBatchSize = 100
+	// DefaultTimeout is the deadline of the enrichment of the batch.
+	DefaultTimeout = 10 * time.Second
+)
+
+type Option func(s *Service) error
+
+// WithJob sets the name the progress of the pass is stored by,
+// the passes with different names are resumed independently.
+func WithJob(name string) Option {
+	return func(s *Service) error {
+		s.name = name
+		return nil
+	}
+}
+
+// WithBatch sets the number of people re-enriched at once
+// and the deadline of their enrichment.
+func WithBatch(size int, timeout time.Duration) Option {
+	return func(s *Service) error {
+		if size < 1 {
+			return ErrBatchSize
+		}
+
+		s.batchSize = size
+		s.timeout = timeout
+		return nil
+	}
+}
+
+// WithEnricher injects the enricher filling the person fields,
+// usually the client.Registry of the configured providers.
+func WithEnricher(enricher client.Enricher) Option {
+	return func(s *Service) error {
+		if enricher == nil {
+			return ErrNilEnricher
+		}
+
+		s.enricher = enricher
+		return nil
+	}
+}
+
+// WithCache injects the cache of the responses of the providers,
+// the cached responses for the re-enriched names are removed before querying
+// the cached updated people are removed and the cached pages and statistics of the people are invalidated.
+func WithCache(c cache.Cache, providers ...string) Option {
+	return func(s *Service) error {
+		s.cache = c
+		s.providers = providers
+		s.generation = cache.NewGeneration(c, models.PeopleGeneration)
+		return nil
+	}
+}
+
+func WithPeopleStorage(people person.Storage) Option {
+	return func(s *Service) error {
+		if people == nil {
+			return service.ErrNilPeopleStorage
+		}
+
+		s.people = people
+		return nil
+	}
+}
+
+// WithJobStorage injects the storage of the progress of the pass.
+func WithJobStorage(jobs job.Storage) Option {
+	return func(s *Service) error {
+		if jobs == nil {
+			return service.ErrNilJobStorage
+		}
+
+		s.jobs = jobs
+		return nil
+	}
+}
+
+// WithPostgresStorage injects postgres people and job storages.
+func WithPostgresStorage(url string) Option {
+	return func(s *Service) error {
+		db, err := storage.NewPostgresPool(url)
+		if err != nil {
+			return err
+		}
+
+		people, err := pg.New(db)
+		if err != nil {
+			return err
+		}
+
+		jobs, err := jobpg.New(db)
+		if err != nil {
+			return err
+		}
+
+		s.people = people
+		s.jobs = jobs
+		return nil
+	}
+}
+
+// Service enriches again the stored people missing the enriched fields
+// or enriched before the cutoff.
+type Service struct {
+	name      string
+	batchSize int
+	timeout   time.Duration
+
+	enricher   client.Enricher
+	cache      cache.Cache
+	providers  []string
+	generation *cache.Generation
+
+	people person.Storage
+	jobs   job.Storage
+}
+
+func New(options ...Option) (*Service, error) {
+	s := &Service{
+		name:      DefaultJob,
+		batchSize: DefaultBatchSize,
+		timeout:   DefaultTimeout,
+	}
+
+	for _, option := range options {
+		if err := option(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Run re-enriches the people missing the enriched fields or enriched before the cutoff
+// by batches and returns the number of the updated people.
+//
+// The progress is stored after every batch, so the interrupted pass
+// is resumed after the last processed person with its original cutoff.
+// The rate limited enrichment waits until the limit resets.
+func (s *Service) Run(ctx context.Context, cutoff time.Time) (int, error) {
+	j, err := s.jobs.FindByName(ctx, s.name)
+	if errors.Is(err, job.ErrNotFound) {
+		j = &models.Job{Name: s.name, Cutoff: cutoff}
+	} else if err != nil {
+		return 0, fmt.Errorf("Service.Run: %w", err)
+	}
+
+	updated := 0
+	for {
+		people, err := s.people.ListStale(ctx, j.Cutoff, j.LastID, s.batchSize)
+		if err != nil {
+			return updated, fmt.Errorf("Service.Run: %w", err)
+		}
+
+		if len(people) == 0 {
+			if err = s.jobs.Delete(ctx, s.name); err != nil {
+				return updated, fmt.Errorf("Service.Run: %w", err)
+			}
+
+			return updated, nil
+		}
+
+		n, err := s.reenrich(ctx, people, false)
+		updated += n
+		if err != nil {
+			return updated, fmt.Errorf("Service.Run: %w", err)
+		}
+
+		j.LastID = people[len(people)-1].ID
+		if err = s.jobs.Save(ctx, j); err != nil {
+			return updated, fmt.Errorf("Service.Run: %w", err)
+		}
+	}
+}
+
+// RunPartial re-enriches the people stored without the fields of any of the providers
+// and not tried since given time by batches and returns the number of the updated people.
+//
+// Only the providers failed for the person are queried again, the tried people
+// are updated even if the providers still fail, so they are skipped until the next interval.
+// The pass is not stored, every run starts from the first person.
+// The rate limited enrichment waits until the limit resets.
+func (s *Service) RunPartial(ctx context.Context, providers []string, triedBefore time.Time) (int, error) {
+	updated := 0
+	after := ""
+	for {
+		people, err := s.people.ListPartial(ctx, providers, triedBefore, after, s.batchSize)
+		if err != nil {
+			return updated, fmt.Errorf("Service.RunPartial: %w", err)
+		}
+
+		if len(people) == 0 {
+			return updated, nil
+		}
+
+		n, err := s.reenrich(ctx, people, true)
+		updated += n
+		if err != nil {
+			return updated, fmt.Errorf("Service.RunPartial: %w", err)
+		}
+
+		after = people[len(people)-1].ID
+	}
+}
+
+// reenrich enriches the people again and updates the ones
+// with any of the fields refreshed, the fields of the failed providers
+// and the fields set manually are kept.
+//
+// The failures of the providers the person was stored without are kept in the status
+// while they still fail, the failures of the succeeded ones are removed.
+// If partial is set only the providers failed for the person are queried
+// and the person is updated even if nothing is refreshed.
+func (s *Service) reenrich(ctx context.Context, people []models.Person, partial bool) (int, error) {
+	updated := 0
+	for _, g := range s.group(people, partial) {
+		s.invalidate(ctx, g.people, g.providers)
+
+		batch := make([]*models.Person, len(g.people))
+		for i, p := range g.people {
+			batch[i] = &models.Person{
+				ID:         p.ID,
+				Name:       p.Name,
+				Surname:    p.Surname,
+				Patronymic: p.Patronymic,
+			}
+		}
+
+		errs, err := s.enrich(ctx, g.enricher, batch)
+		if err != nil {
+			return updated, err
+		}
+
+		for i := range g.people {
+			p := &g.people[i]
+
+			refreshed := false
+			for _, f := range g.enricher.Fields() {
+				if client.Filled(batch[i], f) && !p.Provenance.Manual(string(f)) {
+					client.CopyField(p, batch[i], f)
+					refreshed = true
+				}
+			}
+
+			if !refreshed && !partial {
+				continue
+			}
+
+			failed := make(map[string]string)
+			for _, pe := range client.ProviderErrors(errs[i]) {
+				failed[pe.Provider] = pe.Err.Error()
+			}
+
+			status := make(models.EnrichmentStatus, len(p.EnrichmentStatus))
+			for provider := range p.EnrichmentStatus {
+				if reason, ok := failed[provider]; ok {
+					status[provider] = reason
+				}
+			}
+			p.EnrichmentStatus = status
+			p.EnrichedAt = time.Now()
+
+			if err = s.people.Update(ctx, p); err != nil {
+				return updated, err
+			}
+			updated++
+
+			if s.cache != nil {
+				// the cached person expires anyway, so the cache failures are ignored
+				_ = s.cache.Del(ctx, p.ID)
+			}
+		}
+	}
+
+	if updated > 0 && s.generation != nil {
+		// the cached pages and statistics expire anyway, so the cache failures are ignored
+		_ = s.generation.Bump(ctx)
+	}
+
+	return updated, nil
+}
+
+// group is the people enriched by the same providers.
+type group struct {
+	enricher  client.Enricher
+	providers []string
+	people    []models.Person
+}
+
+// group returns the people enriched by all the providers
+// or, if partial is set, grouped by the providers failed for them.
+func (s *Service) group(people []models.Person, partial bool) []group {
+	if !partial {
+		return []group{{enricher: s.enricher, providers: s.providers, people: people}}
+	}
+
+	var groups []group
+	index := make(map[string]int)
+	for _, p := range people {
+		providers := make([]string, 0, len(p.EnrichmentStatus))
+		for provider := range p.EnrichmentStatus {
+			providers = append(providers, provider)
+		}
+		slices.Sort(providers)
+
+		key := strings.Join(providers, ",")
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, group{enricher: s.only(providers), providers: providers})
+		}
+		groups[i].people = append(groups[i].people, p)
+	}
+
+	return groups
+}
+
+// only returns the enricher of given providers if the enricher is the client.Registry,
+// otherwise the enricher itself is returned.
+func (s *Service) only(providers []string) client.Enricher {
+	if r, ok := s.enricher.(*client.Registry); ok {
+		return r.Only(providers...)
+	}
+
+	return s.enricher
+}
+
+// enrich enriches the people by the enricher and returns the error of each person.
+//
+// The people failed by the rate limit are enriched again after it resets,
+// the error is returned only if the context is done while waiting.
+func (s *Service) enrich(ctx context.Context, enricher client.Enricher, people []*models.Person) ([]error, error) {
+	errs := make([]error, len(people))
+
+	indexes := make([]int, len(people))
+	for i := range people {
+		indexes[i] = i
+	}
+
+	for {
+		batch := make([]*models.Person, len(indexes))
+		for j, i := range indexes {
+			batch[j] = people[i]
+		}
+
+		enrichCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		batchErrs := client.EnrichBatch(enrichCtx, enricher, batch)
+		cancel()
+
+		var wait time.Duration
+		limited := make([]int, 0, len(indexes))
+		for j, i := range indexes {
+			errs[i] = batchErrs[j]
+			if retryAfter, ok := client.RetryAfter(batchErrs[j]); ok {
+				limited = append(limited, i)
+				wait = max(wait, retryAfter)
+			}
+		}
+
+		if len(limited) == 0 {
+			return errs, nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		indexes = limited
+	}
+}
+
+// invalidate removes the cached responses of the providers for the names of the people,
+// the cache failures are ignored.
+func (s *Service) invalidate(ctx context.Context, people []models.Person, providers []string) {
+	if s.cache == nil {
+		return
+	}
+
+	for _, p := range people {
+		for _, provider := range providers {
+			_ = s.cache.Del(ctx, client.CacheKey(provider, p.Name))
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package reenrich
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/insan1a/exile/internal/client"
+	clientmocks "github.com/insan1a/exile/internal/client/mocks"
+	"github.com/insan1a/exile/internal/models"
+	cachemocks "github.com/insan1a/exile/internal/storage/cache/mocks"
+	"github.com/insan1a/exile/internal/storage/job"
+	jobmocks "github.com/insan1a/exile/internal/storage/job/mocks"
+	storagemocks "github.com/insan1a/exile/internal/storage/person/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRegistry(t *testing.T, agify, genderize client.Fetcher) *client.Registry {
+	t.Helper()
+
+	registry, err := client.NewRegistry(
+		client.NewFetcherEnricher(client.ProviderAgify, agify, client.FieldAge),
+		client.NewFetcherEnricher(client.ProviderGenderize, genderize, client.FieldGender),
+	)
+	if err != nil {
+		t.Fatalf("client.NewRegistry() error = %v", err)
+	}
+
+	return registry
+}
+
+// updated matches the updated person ignoring the time of the enrichment.
+func updated(want models.Person) any {
+	return mock.MatchedBy(func(p *models.Person) bool {
+		if p.EnrichedAt.IsZero() {
+			return false
+		}
+
+		got := *p
+		got.EnrichedAt = time.Time{}
+		return got.ID == want.ID &&
+			got.Age == want.Age &&
+			got.Gender == want.Gender &&
+			len(got.EnrichmentStatus) == len(want.EnrichmentStatus)
+	})
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		wantErr error
+	}{
+		{
+			name:    "nil enricher",
+			options: []Option{WithEnricher(nil)},
+			wantErr: ErrNilEnricher,
+		},
+		{
+			name:    "zero batch",
+			options: []Option{WithBatch(0, time.Second)},
+			wantErr: ErrBatchSize,
+		},
+		{
+			name:    "defaults",
+			options: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.options...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_Run(t *testing.T) {
+	people := storagemocks.NewStorage(t)
+	jobs := jobmocks.NewStorage(t)
+	cache := cachemocks.NewCache(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithPeopleStorage(people),
+		WithJobStorage(jobs),
+		WithCache(cache, client.ProviderAgify, client.ProviderGenderize),
+		WithEnricher(newRegistry(t, agify, genderize)),
+		WithBatch(2, time.Second),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	roman := models.Person{
+		ID:               "1",
+		Name:             "Roman",
+		Age:              20,
+		EnrichmentStatus: models.EnrichmentStatus{client.ProviderGenderize: "timeout"},
+	}
+	olga := models.Person{ID: "2", Name: "Olga", Age: 30, Gender: "female"}
+
+	jobs.On("FindByName", context.Background(), DefaultJob).Once().Return(nil, job.ErrNotFound)
+	people.On("ListStale", context.Background(), cutoff, "", 2).Once().
+		Return([]models.Person{roman, olga}, nil)
+	people.On("ListStale", context.Background(), cutoff, "2", 2).Once().Return(nil, nil)
+
+	cache.On("Del", context.Background(), "enrichment:agify:roman").Once().Return(nil)
+	cache.On("Del", context.Background(), "enrichment:genderize:roman").Once().Return(nil)
+	cache.On("Del", context.Background(), "enrichment:agify:olga").Once().Return(nil)
+	cache.On("Del", context.Background(), "enrichment:genderize:olga").Once().Return(nil)
+
+	agify.On("Fetch", mock.Anything, "Roman").Once().Return([]byte(`{"age":25}`), nil)
+	agify.On("Fetch", mock.Anything, "Olga").Once().Return(nil, client.ErrFindAge)
+	genderize.On("Fetch", mock.Anything, "Roman").Once().Return([]byte(`{"gender":"male"}`), nil)
+	genderize.On("Fetch", mock.Anything, "Olga").Once().Return(nil, client.ErrFindGender)
+
+	people.On("Update", context.Background(), updated(models.Person{ID: "1", Age: 25, Gender: "male"})).
+		Once().Return(nil)
+	cache.On("Del", context.Background(), "1").Once().Return(nil)
+	cache.On("Set", context.Background(), models.PeopleGeneration, mock.Anything, time.Duration(0)).
+		Once().Return(nil)
+	jobs.On("Save", context.Background(), &models.Job{Name: DefaultJob, Cutoff: cutoff, LastID: "2"}).
+		Once().Return(nil)
+	jobs.On("Delete", context.Background(), DefaultJob).Once().Return(nil)
+
+	n, err := svc.Run(context.Background(), cutoff)
+	if err != nil {
+		t.Fatalf("svc.Run() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("svc.Run() updated %d people, want 1", n)
+	}
+}
+
+func TestService_RunResume(t *testing.T) {
+	people := storagemocks.NewStorage(t)
+	jobs := jobmocks.NewStorage(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithPeopleStorage(people),
+		WithJobStorage(jobs),
+		WithEnricher(newRegistry(t, agify, genderize)),
+		WithBatch(1, time.Second),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	stored := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ivan := models.Person{ID: "3", Name: "Ivan"}
+
+	jobs.On("FindByName", context.Background(), DefaultJob).Once().
+		Return(&models.Job{Name: DefaultJob, Cutoff: stored, LastID: "2"}, nil)
+	people.On("ListStale", context.Background(), stored, "2", 1).Once().Return([]models.Person{ivan}, nil)
+	people.On("ListStale", context.Background(), stored, "3", 1).Once().Return(nil, nil)
+
+	agify.On("Fetch", mock.Anything, "Ivan").Once().
+		Return(nil, &client.RateLimitError{API: "agify", RetryAfter: 10 * time.Millisecond})
+	agify.On("Fetch", mock.Anything, "Ivan").Once().Return([]byte(`{"age":40}`), nil)
+	genderize.On("Fetch", mock.Anything, "Ivan").Twice().Return([]byte(`{"gender":"male"}`), nil)
+
+	people.On("Update", context.Background(), updated(models.Person{ID: "3", Age: 40, Gender: "male"})).
+		Once().Return(nil)
+	jobs.On("Save", context.Background(), &models.Job{Name: DefaultJob, Cutoff: stored, LastID: "3"}).
+		Once().Return(nil)
+	jobs.On("Delete", context.Background(), DefaultJob).Once().Return(nil)
+
+	n, err := svc.Run(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("svc.Run() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("svc.Run() updated %d people, want 1", n)
+	}
+}
+
+func TestService_RunManual(t *testing.T) {
+	people := storagemocks.NewStorage(t)
+	jobs := jobmocks.NewStorage(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithPeopleStorage(people),
+		WithJobStorage(jobs),
+		WithEnricher(newRegistry(t, agify, genderize)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	corrected := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	ivan := models.Person{
+		ID:         "1",
+		Name:       "Ivan",
+		Age:        33,
+		Gender:     "female",
+		Provenance: models.Provenance{"age": {Source: models.SourceManual, UpdatedAt: corrected}},
+	}
+
+	jobs.On("FindByName", context.Background(), DefaultJob).Once().Return(nil, job.ErrNotFound)
+	people.On("ListStale", context.Background(), cutoff, "", DefaultBatchSize).Once().
+		Return([]models.Person{ivan}, nil)
+	people.On("ListStale", context.Background(), cutoff, "1", DefaultBatchSize).Once().Return(nil, nil)
+
+	agify.On("Fetch", mock.Anything, "Ivan").Once().Return([]byte(`{"age":40}`), nil)
+	genderize.On("Fetch", mock.Anything, "Ivan").Once().Return([]byte(`{"gender":"male"}`), nil)
+
+	people.On("Update", context.Background(), mock.MatchedBy(func(p *models.Person) bool {
+		return p.Age == 33 && p.Gender == "male" &&
+			p.Provenance["age"] == ivan.Provenance["age"] &&
+			p.Provenance["gender"].Source == client.ProviderGenderize
+	})).Once().Return(nil)
+	jobs.On("Save", context.Background(), &models.Job{Name: DefaultJob, Cutoff: cutoff, LastID: "1"}).
+		Once().Return(nil)
+	jobs.On("Delete", context.Background(), DefaultJob).Once().Return(nil)
+
+	if _, err = svc.Run(context.Background(), cutoff); err != nil {
+		t.Fatalf("svc.Run() error = %v", err)
+	}
+}
+
+func TestService_RunCanceled(t *testing.T) {
+	people := storagemocks.NewStorage(t)
+	jobs := jobmocks.NewStorage(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithPeopleStorage(people),
+		WithJobStorage(jobs),
+		WithEnricher(newRegistry(t, agify, genderize)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	cutoff := time.Now()
+	jobs.On("FindByName", ctx, DefaultJob).Once().Return(nil, job.ErrNotFound)
+	people.On("ListStale", ctx, cutoff, "", DefaultBatchSize).Once().
+		Return([]models.Person{{ID: "1", Name: "Ivan"}}, nil)
+	agify.On("Fetch", mock.Anything, "Ivan").Once().
+		Return(nil, &client.RateLimitError{API: "agify", RetryAfter: time.Minute})
+	genderize.On("Fetch", mock.Anything, "Ivan").Once().Return([]byte(`{"gender":"male"}`), nil)
+
+	if _, err = svc.Run(ctx, cutoff); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("svc.Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestService_RunPartial(t *testing.T) {
+	people := storagemocks.NewStorage(t)
+	agify := clientmocks.NewFetcher(t)
+	genderize := clientmocks.NewFetcher(t)
+
+	svc, err := New(
+		WithPeopleStorage(people),
+		WithEnricher(newRegistry(t, agify, genderize)),
+		WithBatch(1, time.Second),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	providers := []string{client.ProviderGenderize}
+	triedBefore := time.Now().Add(-time.Hour)
+	roman := models.Person{
+		ID:               "1",
+		Name:             "Roman",
+		Age:              25,
+		EnrichmentStatus: models.EnrichmentStatus{client.ProviderGenderize: "timeout"},
+	}
+	ivan := models.Person{
+		ID:               "2",
+		Name:             "Ivan",
+		Age:              30,
+		EnrichmentStatus: models.EnrichmentStatus{client.ProviderGenderize: "timeout"},
+	}
+
+	people.On("ListPartial", context.Background(), providers, triedBefore, "", 1).Once().Return([]models.Person{roman}, nil)
+	people.On("ListPartial", context.Background(), providers, triedBefore, "1", 1).Once().Return([]models.Person{ivan}, nil)
+	people.On("ListPartial", context.Background(), providers, triedBefore, "2", 1).Once().Return(nil, nil)
+
+	// only the failed provider is queried again
+	genderize.On("Fetch", mock.Anything, "Roman").Once().Return([]byte(`{"gender":"male"}`), nil)
+	genderize.On("Fetch", mock.Anything, "Ivan").Once().Return(nil, errors.New("timeout"))
+
+	people.On("Update", context.Background(), updated(models.Person{ID: "1", Age: 25, Gender: "male"})).
+		Once().Return(nil)
+	// the still failed person is updated to be skipped until the next interval
+	people.On("Update", context.Background(), updated(models.Person{
+		ID:               "2",
+		Age:              30,
+		EnrichmentStatus: models.EnrichmentStatus{client.ProviderGenderize: "timeout"},
+	})).Once().Return(nil)
+
+	n, err := svc.RunPartial(context.Background(), providers, triedBefore)
+	if err != nil {
+		t.Fatalf("svc.RunPartial() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("svc.RunPartial() updated %d people, want 2", n)
+	}
+}
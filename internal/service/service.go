@@ -3,10 +3,27 @@ package service
 import "errors"
 
 var (
-	ErrNilKafkaConfig   = errors.New("the kafka config could not be nil")
-	ErrNilKafkaTopics   = errors.New("the kafka topics could not be nil")
-	ErrZeroTopics       = errors.New("the kafka topics could not be empty")
-	ErrNilConsumer      = errors.New("the kafka consumer could not be nil")
-	ErrNilProducer      = errors.New("the kafka producer could not be nil")
-	ErrNilPeopleStorage = errors.New("the people storage could not be nil")
+	ErrNilKafkaConfig    = errors.New("the kafka config could not be nil")
+	ErrNilKafkaTopics    = errors.New("the kafka topics could not be nil")
+	ErrZeroTopics        = errors.New("the kafka topics could not be empty")
+	ErrNilConsumer       = errors.New("the kafka consumer could not be nil")
+	ErrNilProducer       = errors.New("the kafka producer could not be nil")
+	ErrNilPeopleStorage  = errors.New("the people storage could not be nil")
+	ErrNilRequestStorage = errors.New("the request storage could not be nil")
+	ErrNilJobStorage     = errors.New("the job storage could not be nil")
+	ErrRepliesDisabled   = errors.New("the replies to the requests are disabled")
+	ErrReplyTimeout      = errors.New("the reply to the request timed out")
 )
+
+// RequestError is returned when the person service failed to process the request.
+type RequestError struct {
+	// ID of the request
+	ID string
+	// Reason of the failure reported by the person service
+	Reason string
+}
+
+// Error returns the reason of the failure.
+func (e *RequestError) Error() string {
+	return e.Reason
+}
@@ -4,14 +4,40 @@ import (
 	"time"
 )
 
+// Message represents a message passed through the broker.
+type Message struct {
+	// Key of the message. It is used as the correlation ID of the request.
+	Key string
+	// Value of the message.
+	Value []byte
+	// Headers of the message.
+	Headers map[string]string
+
+	// Topic, Partition and Offset locate the consumed message in the broker.
+	Topic     string
+	Partition int32
+	Offset    int64
+}
+
 //go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name Consumer --output ./mocks --outpkg mocks
 type Consumer interface {
-	Consume(timeout time.Duration) ([]byte, error)
+	Consume(timeout time.Duration) (*Message, error)
+	// Pause stops fetching the messages of the assigned partitions.
+	Pause() error
+	// Resume continues fetching the messages of the assigned partitions.
+	Resume() error
+	// Commit marks the consumed message processed, its offset is committed
+	// once the earlier messages of the partition are processed too.
+	Commit(msg *Message) error
+	// Rewind pauses the partition of the consumed message and seeks it back to the message,
+	// the message is consumed again after Resume.
+	Rewind(msg *Message) error
 	Close() error
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name Producer --output ./mocks --outpkg mocks
 type Producer interface {
 	Produce(message []byte) error
+	ProduceMessage(message *Message) error
 	Close() error
 }
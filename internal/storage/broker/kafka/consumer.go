@@ -4,22 +4,122 @@ import (
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/insan1a/exile/internal/storage/broker"
 )
 
+const seekTimeout = 5 * time.Second
+
 type Consumer struct {
 	c *kafka.Consumer
+	// offsets tracks the consumed messages when the offsets are stored manually.
+	offsets *offsets
+}
+
+type ConsumerOption func(c *Consumer)
+
+// WithManualCommit stores the offsets of the processed messages only,
+// the consumer must be created with enable.auto.offset.store disabled.
+func WithManualCommit() ConsumerOption {
+	return func(c *Consumer) {
+		c.offsets = newOffsets()
+	}
 }
 
-func NewConsumer(c *kafka.Consumer) *Consumer {
-	return &Consumer{c: c}
+func NewConsumer(c *kafka.Consumer, options ...ConsumerOption) *Consumer {
+	consumer := &Consumer{c: c}
+	for _, option := range options {
+		option(consumer)
+	}
+
+	return consumer
 }
 
-func (c *Consumer) Consume(timeout time.Duration) ([]byte, error) {
+func (c *Consumer) Consume(timeout time.Duration) (*broker.Message, error) {
 	msg, err := c.c.ReadMessage(timeout)
 	if err != nil {
 		return nil, err
 	}
-	return msg.Value, nil
+
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	m := &broker.Message{
+		Key:       string(msg.Key),
+		Value:     msg.Value,
+		Headers:   headers,
+		Partition: msg.TopicPartition.Partition,
+		Offset:    int64(msg.TopicPartition.Offset),
+	}
+	if msg.TopicPartition.Topic != nil {
+		m.Topic = *msg.TopicPartition.Topic
+	}
+
+	if c.offsets != nil {
+		c.offsets.consumed(partition{m.Topic, m.Partition}, m.Offset)
+	}
+
+	return m, nil
+}
+
+// Commit stores the offset of the processed message to be committed,
+// it is no-op unless the offsets are stored manually.
+func (c *Consumer) Commit(msg *broker.Message) error {
+	if c.offsets == nil {
+		return nil
+	}
+
+	offset, ok := c.offsets.processed(partition{msg.Topic, msg.Partition}, msg.Offset)
+	if !ok {
+		return nil
+	}
+
+	_, err := c.c.StoreOffsets([]kafka.TopicPartition{{
+		Topic:     &msg.Topic,
+		Partition: msg.Partition,
+		Offset:    kafka.Offset(offset),
+	}})
+	return err
+}
+
+// Rewind pauses the partition of the message and seeks it back to the message.
+func (c *Consumer) Rewind(msg *broker.Message) error {
+	tp := kafka.TopicPartition{
+		Topic:     &msg.Topic,
+		Partition: msg.Partition,
+		Offset:    kafka.Offset(msg.Offset),
+	}
+
+	if err := c.c.Pause([]kafka.TopicPartition{tp}); err != nil {
+		return err
+	}
+
+	if c.offsets != nil {
+		c.offsets.forget(partition{msg.Topic, msg.Partition}, msg.Offset)
+	}
+
+	return c.c.Seek(tp, int(seekTimeout.Milliseconds()))
+}
+
+// Pause pauses the assigned partitions, the consumer keeps polling to stay in the group.
+func (c *Consumer) Pause() error {
+	partitions, err := c.c.Assignment()
+	if err != nil {
+		return err
+	}
+
+	return c.c.Pause(partitions)
+}
+
+// Resume resumes the assigned partitions.
+func (c *Consumer) Resume() error {
+	partitions, err := c.c.Assignment()
+	if err != nil {
+		return err
+	}
+
+	return c.c.Resume(partitions)
 }
 
 func (c *Consumer) Close() error {
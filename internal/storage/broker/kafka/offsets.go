@@ -0,0 +1,78 @@
+package kafka
+
+import "sync"
+
+type partition struct {
+	topic     string
+	partition int32
+}
+
+// offsets tracks the consumed messages of the partitions
+// so that only the offsets of the processed messages are stored.
+type offsets struct {
+	mu         sync.Mutex
+	partitions map[partition]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	// unprocessed holds the offsets of the consumed messages not processed yet.
+	unprocessed map[int64]struct{}
+	// next is the offset following the last processed message.
+	next int64
+	// stored is the last stored offset.
+	stored int64
+}
+
+func newOffsets() *offsets {
+	return &offsets{partitions: make(map[partition]*partitionOffsets)}
+}
+
+func (o *offsets) get(p partition) *partitionOffsets {
+	po, ok := o.partitions[p]
+	if !ok {
+		po = &partitionOffsets{unprocessed: make(map[int64]struct{}), stored: -1}
+		o.partitions[p] = po
+	}
+
+	return po
+}
+
+// consumed marks the message with the offset consumed.
+func (o *offsets) consumed(p partition, offset int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.get(p).unprocessed[offset] = struct{}{}
+}
+
+// forget drops the consumed message with the offset, it is consumed again.
+func (o *offsets) forget(p partition, offset int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.get(p).unprocessed, offset)
+}
+
+// processed marks the message with the offset processed and returns the offset to store,
+// it stops at the first unprocessed message of the partition.
+// The false is returned if the offset to store is not advanced.
+func (o *offsets) processed(p partition, offset int64) (int64, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	po := o.get(p)
+	delete(po.unprocessed, offset)
+	po.next = max(po.next, offset+1)
+
+	commit := po.next
+	for unprocessed := range po.unprocessed {
+		commit = min(commit, unprocessed)
+	}
+
+	if commit <= po.stored {
+		return 0, false
+	}
+
+	po.stored = commit
+	return commit, true
+}
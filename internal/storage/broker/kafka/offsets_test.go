@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsets_processed(t *testing.T) {
+	p := partition{topic: "topic", partition: 0}
+
+	type want struct {
+		offset int64
+		ok     bool
+	}
+	tests := []struct {
+		name      string
+		consumed  []int64
+		forgotten []int64
+		processed []int64
+		want      []want
+	}{
+		{
+			name:      "in order",
+			consumed:  []int64{1, 2},
+			processed: []int64{1, 2},
+			want:      []want{{2, true}, {3, true}},
+		},
+		{
+			name:      "stops at the unprocessed message",
+			consumed:  []int64{1, 2, 3},
+			processed: []int64{2, 3, 1},
+			want:      []want{{1, true}, {0, false}, {4, true}},
+		},
+		{
+			name:      "rewound message is not waited for",
+			consumed:  []int64{1, 2},
+			forgotten: []int64{1},
+			processed: []int64{2},
+			want:      []want{{3, true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOffsets()
+			for _, offset := range tt.consumed {
+				o.consumed(p, offset)
+			}
+			for _, offset := range tt.forgotten {
+				o.forget(p, offset)
+			}
+
+			for i, offset := range tt.processed {
+				got, ok := o.processed(p, offset)
+				assert.Equal(t, tt.want[i], want{got, ok})
+			}
+		})
+	}
+}
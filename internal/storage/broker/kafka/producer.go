@@ -2,28 +2,74 @@ package kafka
 
 import (
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/insan1a/exile/internal/storage/broker"
 )
 
 type Producer struct {
 	p     *kafka.Producer
 	topic string
+	// sync waits for the delivery report of every message.
+	sync bool
 }
 
-func NewProducer(p *kafka.Producer, topic string) *Producer {
-	return &Producer{
+type ProducerOption func(p *Producer)
+
+// WithSyncDelivery makes ProduceMessage wait until the message is delivered
+// and return the delivery error.
+func WithSyncDelivery() ProducerOption {
+	return func(p *Producer) {
+		p.sync = true
+	}
+}
+
+func NewProducer(p *kafka.Producer, topic string, options ...ProducerOption) *Producer {
+	producer := &Producer{
 		p:     p,
 		topic: topic,
 	}
+	for _, option := range options {
+		option(producer)
+	}
+
+	return producer
 }
 
 func (p *Producer) Produce(msg []byte) error {
-	return p.p.Produce(&kafka.Message{
+	return p.ProduceMessage(&broker.Message{Value: msg})
+}
+
+// ProduceMessage produces the message with its key and headers to the topic.
+func (p *Producer) ProduceMessage(msg *broker.Message) error {
+	km := &kafka.Message{
 		TopicPartition: kafka.TopicPartition{
 			Topic:     &p.topic,
 			Partition: kafka.PartitionAny,
 		},
-		Value: msg,
-	}, nil)
+		Value: msg.Value,
+	}
+
+	if msg.Key != "" {
+		km.Key = []byte(msg.Key)
+	}
+
+	for k, v := range msg.Headers {
+		km.Headers = append(km.Headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	if !p.sync {
+		return p.p.Produce(km, nil)
+	}
+
+	delivery := make(chan kafka.Event, 1)
+	if err := p.p.Produce(km, delivery); err != nil {
+		return err
+	}
+
+	if m, ok := (<-delivery).(*kafka.Message); ok && m.TopicPartition.Error != nil {
+		return m.TopicPartition.Error
+	}
+
+	return nil
 }
 
 func (p *Producer) Close() error {
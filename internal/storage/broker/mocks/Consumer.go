@@ -5,6 +5,7 @@ package mocks
 import (
 	time "time"
 
+	broker "github.com/insan1a/exile/internal/storage/broker"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -27,20 +28,34 @@ func (_m *Consumer) Close() error {
 	return r0
 }
 
+// Commit provides a mock function with given fields: msg
+func (_m *Consumer) Commit(msg *broker.Message) error {
+	ret := _m.Called(msg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*broker.Message) error); ok {
+		r0 = rf(msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Consume provides a mock function with given fields: timeout
-func (_m *Consumer) Consume(timeout time.Duration) ([]byte, error) {
+func (_m *Consumer) Consume(timeout time.Duration) (*broker.Message, error) {
 	ret := _m.Called(timeout)
 
-	var r0 []byte
+	var r0 *broker.Message
 	var r1 error
-	if rf, ok := ret.Get(0).(func(time.Duration) ([]byte, error)); ok {
+	if rf, ok := ret.Get(0).(func(time.Duration) (*broker.Message, error)); ok {
 		return rf(timeout)
 	}
-	if rf, ok := ret.Get(0).(func(time.Duration) []byte); ok {
+	if rf, ok := ret.Get(0).(func(time.Duration) *broker.Message); ok {
 		r0 = rf(timeout)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
+			r0 = ret.Get(0).(*broker.Message)
 		}
 	}
 
@@ -53,6 +68,48 @@ func (_m *Consumer) Consume(timeout time.Duration) ([]byte, error) {
 	return r0, r1
 }
 
+// Pause provides a mock function with given fields:
+func (_m *Consumer) Pause() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resume provides a mock function with given fields:
+func (_m *Consumer) Resume() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Rewind provides a mock function with given fields: msg
+func (_m *Consumer) Rewind(msg *broker.Message) error {
+	ret := _m.Called(msg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*broker.Message) error); ok {
+		r0 = rf(msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewConsumer interface {
 	mock.TestingT
 	Cleanup(func())
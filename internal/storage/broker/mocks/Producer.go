@@ -2,7 +2,10 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	broker "github.com/insan1a/exile/internal/storage/broker"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Producer is an autogenerated mock type for the Producer type
 type Producer struct {
@@ -37,6 +40,20 @@ func (_m *Producer) Produce(message []byte) error {
 	return r0
 }
 
+// ProduceMessage provides a mock function with given fields: message
+func (_m *Producer) ProduceMessage(message *broker.Message) error {
+	ret := _m.Called(message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*broker.Message) error); ok {
+		r0 = rf(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewProducer interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Generation is the version of the cached values derived from many stored records,
+// the writers bump it to invalidate all of them at once instead of tracking their keys.
+type Generation struct {
+	cache Cache
+	key   string
+}
+
+// NewGeneration returns the generation stored in the cache by the key.
+func NewGeneration(c Cache, key string) *Generation {
+	return &Generation{cache: c, key: key}
+}
+
+// Key returns the key of the cached value in the current generation.
+func (g *Generation) Key(ctx context.Context, key string) (string, error) {
+	v, found, err := g.cache.Get(ctx, g.key)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		v = []byte("0")
+	}
+
+	return key + "@" + string(v), nil
+}
+
+// Bump starts the new generation, the values cached in the previous ones are never read again
+// and expire by their ttl.
+func (g *Generation) Bump(ctx context.Context) error {
+	return g.cache.Set(ctx, g.key, []byte(strconv.FormatInt(time.Now().UnixNano(), 10)), 0)
+}
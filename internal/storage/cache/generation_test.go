@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// mapCache is the cache keeping the values in the map regardless of the ttl.
+type mapCache map[string][]byte
+
+func (c mapCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c[key] = value
+	return nil
+}
+
+func (c mapCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	v, ok := c[key]
+	return v, ok, nil
+}
+
+func (c mapCache) Del(_ context.Context, key string) error {
+	delete(c, key)
+	return nil
+}
+
+func TestGeneration(t *testing.T) {
+	ctx := context.Background()
+	g := NewGeneration(make(mapCache), "generation")
+
+	first, err := g.Key(ctx, "stats")
+	if err != nil {
+		t.Fatalf("Generation.Key() error = %v", err)
+	}
+	if again, _ := g.Key(ctx, "stats"); again != first {
+		t.Errorf("Generation.Key() = %q, want %q without the bump", again, first)
+	}
+
+	if err = g.Bump(ctx); err != nil {
+		t.Fatalf("Generation.Bump() error = %v", err)
+	}
+
+	second, err := g.Key(ctx, "stats")
+	if err != nil {
+		t.Fatalf("Generation.Key() error = %v", err)
+	}
+	if second == first {
+		t.Errorf("Generation.Key() = %q after the bump, want the new key", second)
+	}
+}
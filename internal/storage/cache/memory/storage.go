@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/insan1a/exile/internal/storage"
+)
+
+// Storage is an in-memory LRU cache.
+//
+// When the capacity is reached the least recently used value is evicted.
+type Storage struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// New creates a new in-memory cache holding up to capacity values.
+//
+// If capacity is not positive returns storage.ErrInvalidCapacity.
+func New(capacity int) (*Storage, error) {
+	if capacity <= 0 {
+		return nil, storage.ErrInvalidCapacity
+	}
+
+	return &Storage{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element, capacity),
+	}, nil
+}
+
+// Set stores the value for ttl. The value never expires if ttl is zero.
+func (s *Storage) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := s.items[key]; ok {
+		s.ll.MoveToFront(el)
+		e := el.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		return nil
+	}
+
+	s.items[key] = s.ll.PushFront(&entry{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+
+	if s.ll.Len() > s.capacity {
+		s.remove(s.ll.Back())
+	}
+
+	return nil
+}
+
+func (s *Storage) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	e := el.Value.(*entry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		s.remove(el)
+		return nil, false, nil
+	}
+
+	s.ll.MoveToFront(el)
+	return e.value, true, nil
+}
+
+func (s *Storage) Del(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+
+	return nil
+}
+
+func (s *Storage) remove(el *list.Element) {
+	s.ll.Remove(el)
+	delete(s.items, el.Value.(*entry).key)
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New(0); err == nil {
+		t.Errorf("New() error = nil, want error")
+	}
+}
+
+func TestStorage_Evict(t *testing.T) {
+	s, err := New(2)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	_ = s.Set(ctx, "a", []byte("a"), 0)
+	_ = s.Set(ctx, "b", []byte("b"), 0)
+
+	// "a" becomes the most recently used, so "b" is evicted.
+	if _, found, _ := s.Get(ctx, "a"); !found {
+		t.Fatalf("Get() a not found")
+	}
+	_ = s.Set(ctx, "c", []byte("c"), 0)
+
+	if _, found, _ := s.Get(ctx, "b"); found {
+		t.Errorf("Get() b found, want evicted")
+	}
+	if _, found, _ := s.Get(ctx, "a"); !found {
+		t.Errorf("Get() a not found")
+	}
+	if _, found, _ := s.Get(ctx, "c"); !found {
+		t.Errorf("Get() c not found")
+	}
+}
+
+func TestStorage_Expire(t *testing.T) {
+	s, err := New(1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	_ = s.Set(ctx, "a", []byte("a"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, found, _ := s.Get(ctx, "a"); found {
+		t.Errorf("Get() a found, want expired")
+	}
+}
+
+func TestStorage_Del(t *testing.T) {
+	s, err := New(1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	_ = s.Set(ctx, "a", []byte("a"), 0)
+	_ = s.Del(ctx, "a")
+
+	if _, found, _ := s.Get(ctx, "a"); found {
+		t.Errorf("Get() a found, want deleted")
+	}
+}
@@ -0,0 +1,20 @@
+package job
+
+import (
+	"context"
+	"errors"
+
+	"github.com/insan1a/exile/internal/models"
+)
+
+var (
+	ErrNotFound = errors.New("the job not found")
+	ErrNilJob   = errors.New("the job could not be nil")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name Storage --output ./mocks --outpkg mocks
+type Storage interface {
+	FindByName(context.Context, string) (*models.Job, error)
+	Save(context.Context, *models.Job) error
+	Delete(context.Context, string) error
+}
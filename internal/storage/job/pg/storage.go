@@ -0,0 +1,110 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/storage"
+	"github.com/insan1a/exile/internal/storage/job"
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+// New creates a new job storage.
+//
+// If db is nil returns storage.ErrNilDB.
+func New(db *sql.DB) (*Storage, error) {
+	if db == nil {
+		return nil, storage.ErrNilDB
+	}
+
+	return &Storage{db: db}, nil
+}
+
+// FindByName returns a job by given name.
+//
+// If job not found returns job.ErrNotFound.
+func (s *Storage) FindByName(ctx context.Context, name string) (*models.Job, error) {
+	const query = `
+	SELECT
+		name,
+		cutoff,
+		COALESCE(last_id::text, ''),
+		updated_at
+	FROM reenrich_job
+	WHERE name = $1
+	`
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.FindByName: %w", err)
+	}
+	defer stmt.Close()
+
+	var j models.Job
+	if err = stmt.QueryRowContext(ctx, name).
+		Scan(&j.Name, &j.Cutoff, &j.LastID, &j.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("Storage.FindByName: %w", job.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("Storage.FindByName: %w", err)
+	}
+
+	return &j, nil
+}
+
+// Save creates the job or updates its cutoff and the last processed person.
+//
+// If job is nil returns job.ErrNilJob.
+func (s *Storage) Save(ctx context.Context, j *models.Job) error {
+	if j == nil {
+		return fmt.Errorf("Storage.Save: %w", job.ErrNilJob)
+	}
+
+	const query = `
+	INSERT INTO reenrich_job
+		(name, cutoff, last_id)
+	VALUES
+		($1, $2, NULLIF($3, '')::uuid)
+	ON CONFLICT (name) DO UPDATE SET
+		cutoff = EXCLUDED.cutoff,
+		last_id = EXCLUDED.last_id,
+		updated_at = now()
+	RETURNING updated_at
+	`
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("Storage.Save: %w", err)
+	}
+	defer stmt.Close()
+
+	if err = stmt.QueryRowContext(ctx, j.Name, j.Cutoff, j.LastID).Scan(&j.UpdatedAt); err != nil {
+		return fmt.Errorf("Storage.Save: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a job by name, the missing job is not an error.
+func (s *Storage) Delete(ctx context.Context, name string) error {
+	const query = "DELETE FROM reenrich_job WHERE name = $1"
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("Storage.Delete: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.ExecContext(ctx, name); err != nil {
+		return fmt.Errorf("Storage.Delete: %w", err)
+	}
+
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 
 	models "github.com/insan1a/exile/internal/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Storage is an autogenerated mock type for the Storage type
@@ -14,6 +16,30 @@ type Storage struct {
 	mock.Mock
 }
 
+// Count provides a mock function with given fields: _a0, _a1
+func (_m *Storage) Count(_a0 context.Context, _a1 *models.Filter) (int, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter) (int, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter) int); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Filter) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: _a0, _a1
 func (_m *Storage) Create(_a0 context.Context, _a1 *models.Person) error {
 	ret := _m.Called(_a0, _a1)
@@ -69,22 +95,126 @@ func (_m *Storage) FindByID(_a0 context.Context, _a1 string) (*models.Person, er
 }
 
 // List provides a mock function with given fields: _a0, _a1
-func (_m *Storage) List(_a0 context.Context, _a1 *models.Filter) ([]models.Person, error) {
+func (_m *Storage) List(_a0 context.Context, _a1 *models.Filter) (*models.Page, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 []models.Person
+	var r0 *models.Page
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter) ([]models.Person, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter) (*models.Page, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter) []models.Person); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter) *models.Page); ok {
 		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Page)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Filter) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPartial provides a mock function with given fields: ctx, providers, before, after, limit
+func (_m *Storage) ListPartial(ctx context.Context, providers []string, before time.Time, after string, limit int) ([]models.Person, error) {
+	ret := _m.Called(ctx, providers, before, after, limit)
+
+	var r0 []models.Person
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time, string, int) ([]models.Person, error)); ok {
+		return rf(ctx, providers, before, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time, string, int) []models.Person); ok {
+		r0 = rf(ctx, providers, before, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Person)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, time.Time, string, int) error); ok {
+		r1 = rf(ctx, providers, before, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStale provides a mock function with given fields: ctx, before, after, limit
+func (_m *Storage) ListStale(ctx context.Context, before time.Time, after string, limit int) ([]models.Person, error) {
+	ret := _m.Called(ctx, before, after, limit)
+
+	var r0 []models.Person
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, int) ([]models.Person, error)); ok {
+		return rf(ctx, before, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, int) []models.Person); ok {
+		r0 = rf(ctx, before, after, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Person)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string, int) error); ok {
+		r1 = rf(ctx, before, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: _a0, _a1
+func (_m *Storage) Search(_a0 context.Context, _a1 *models.Search) ([]models.SearchResult, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []models.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Search) ([]models.SearchResult, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Search) []models.SearchResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Search) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stats provides a mock function with given fields: _a0, _a1
+func (_m *Storage) Stats(_a0 context.Context, _a1 *models.Filter) (*models.Stats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *models.Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter) (*models.Stats, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Filter) *models.Stats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Stats)
+		}
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context, *models.Filter) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
@@ -94,6 +224,32 @@ func (_m *Storage) List(_a0 context.Context, _a1 *models.Filter) ([]models.Perso
 	return r0, r1
 }
 
+// Statistics provides a mock function with given fields: _a0, _a1
+func (_m *Storage) Statistics(_a0 context.Context, _a1 *models.Statistics) (*models.StatisticsResult, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *models.StatisticsResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Statistics) (*models.StatisticsResult, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Statistics) *models.StatisticsResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StatisticsResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Statistics) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: _a0, _a1
 func (_m *Storage) Update(_a0 context.Context, _a1 *models.Person) error {
 	ret := _m.Called(_a0, _a1)
@@ -3,6 +3,7 @@ package person
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/insan1a/exile/internal/models"
 )
@@ -18,6 +19,23 @@ type Storage interface {
 	FindByID(context.Context, string) (*models.Person, error)
 	Update(context.Context, *models.Person) error
 	Create(context.Context, *models.Person) error
-	List(context.Context, *models.Filter) ([]models.Person, error)
+	// List returns the page of the people matching the filter,
+	// if the cursor of the filter is malformed returns models.ErrCursor.
+	List(context.Context, *models.Filter) (*models.Page, error)
+	// Count returns the count of all the people matching the filter.
+	Count(context.Context, *models.Filter) (int, error)
+	// Stats returns the total count and the counts by the gender, nationality and age range
+	// of the people matching the filter.
+	Stats(context.Context, *models.Filter) (*models.Stats, error)
+	// Statistics returns the demographics of all the stored people.
+	Statistics(context.Context, *models.Statistics) (*models.StatisticsResult, error)
+	// Search returns the people found by the typo tolerant search ordered by their relevance.
+	Search(context.Context, *models.Search) ([]models.SearchResult, error)
 	Delete(context.Context, string) error
+	// ListPartial returns up to limit people failed to be enriched by any of the providers
+	// and enriched before given time ordered by ID and starting after the person with given ID.
+	ListPartial(ctx context.Context, providers []string, before time.Time, after string, limit int) ([]models.Person, error)
+	// ListStale returns up to limit people missing any of the enriched fields
+	// or enriched before given time ordered by ID and starting after the person with given ID.
+	ListStale(ctx context.Context, before time.Time, after string, limit int) ([]models.Person, error)
 }
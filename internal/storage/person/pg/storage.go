@@ -5,9 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"github.com/Masterminds/squirrel"
 	"log/slog"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 
 	"github.com/insan1a/exile/internal/models"
 	"github.com/insan1a/exile/internal/storage"
@@ -38,10 +42,18 @@ func (s *Storage) FindByID(ctx context.Context, id string) (*models.Person, erro
 		id,
 		name,
 		surname,
-		patronymic,
-		age,
-		gender,
-		nationality
+		COALESCE(patronymic, ''),
+		COALESCE(age, 0),
+		COALESCE(age_count, 0),
+		COALESCE(gender, ''),
+		COALESCE(gender_probability, 0),
+		COALESCE(gender_count, 0),
+		COALESCE(nationality, ''),
+		COALESCE(nationality_probability, 0),
+		COALESCE(nationality_count, 0),
+		enrichment_status,
+		enriched_at,
+		provenance
 	FROM person
 	WHERE id = $1 AND is_deleted = FALSE
 		`
@@ -53,8 +65,7 @@ func (s *Storage) FindByID(ctx context.Context, id string) (*models.Person, erro
 	defer stmt.Close()
 
 	var p models.Person
-	if err = stmt.QueryRowContext(ctx, id).
-		Scan(&p.ID, &p.Name, &p.Surname, &p.Patronymic, &p.Age, &p.Gender, &p.Nationality); err != nil {
+	if err = stmt.QueryRowContext(ctx, id).Scan(personFields(&p)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("Storage.FindByID: %w", person.ErrNotFound)
 		}
@@ -62,11 +73,24 @@ func (s *Storage) FindByID(ctx context.Context, id string) (*models.Person, erro
 		return nil, fmt.Errorf("Storage.FindByID: %w", err)
 	}
 
+	nationalities, err := s.nationalities(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.FindByID: %w", err)
+	}
+	p.Nationalities = nationalities[p.ID]
+
 	return &p, nil
 }
 
 // Update updates a person.
 //
+// The confidence of the age, gender and nationality is updated with them if it is set,
+// otherwise the stored one is kept,
+// the enrichment status is updated if it is not nil,
+// the enrichment time is updated if it is not zero,
+// the sources of the fields in the provenance replace the stored ones
+// and the candidates of the nationality are replaced if they are not nil.
+//
 // If person is nil returns person.ErrNilPerson.
 // If person not found or the person ID is empty string returns person.ErrNotFound.
 func (s *Storage) Update(ctx context.Context, p *models.Person) error {
@@ -97,40 +121,84 @@ func (s *Storage) Update(ctx context.Context, p *models.Person) error {
 		args = append(args, p.Patronymic)
 	}
 
+	set := func(column string, value any) {
+		queryParts = append(queryParts, fmt.Sprintf("%s = $%d", column, len(args)+1))
+		args = append(args, value)
+	}
+
 	if p.Age != 0 {
-		queryParts = append(queryParts, fmt.Sprintf("age = $%d", len(args)+1))
-		args = append(args, p.Age)
+		set("age", p.Age)
+		if p.AgeCount != 0 {
+			set("age_count", p.AgeCount)
+		}
 	}
 
 	if p.Gender != "" {
-		queryParts = append(queryParts, fmt.Sprintf("gender = $%d", len(args)+1))
-		args = append(args, p.Gender)
+		set("gender", p.Gender)
+		if p.GenderProbability != 0 {
+			set("gender_probability", p.GenderProbability)
+		}
+		if p.GenderCount != 0 {
+			set("gender_count", p.GenderCount)
+		}
 	}
 
 	if p.Nationality != "" {
-		queryParts = append(queryParts, fmt.Sprintf("nationality = $%d", len(args)+1))
-		args = append(args, p.Nationality)
+		set("nationality", p.Nationality)
+		if p.NationalityProbability != 0 {
+			set("nationality_probability", p.NationalityProbability)
+		}
+		if p.NationalityCount != 0 {
+			set("nationality_count", p.NationalityCount)
+		}
+	}
+
+	if p.EnrichmentStatus != nil {
+		queryParts = append(queryParts, fmt.Sprintf("enrichment_status = $%d", len(args)+1))
+		args = append(args, p.EnrichmentStatus)
+	}
+
+	if !p.EnrichedAt.IsZero() {
+		queryParts = append(queryParts, fmt.Sprintf("enriched_at = $%d", len(args)+1))
+		args = append(args, p.EnrichedAt)
+	}
+
+	if len(p.Provenance) > 0 {
+		queryParts = append(queryParts, fmt.Sprintf("provenance = provenance || $%d", len(args)+1))
+		args = append(args, p.Provenance)
 	}
 
 	if len(args) == 0 {
 		return nil
 	}
 
+	nationalities := p.Nationalities
+
 	args = append(args, p.ID)
 	query += fmt.Sprintf(
-		"%s WHERE id = $%d RETURNING name, surname, patronymic, age, gender, nationality",
+		`%s WHERE id = $%d RETURNING
+			id, name, surname, COALESCE(patronymic, ''),
+			COALESCE(age, 0), COALESCE(age_count, 0),
+			COALESCE(gender, ''), COALESCE(gender_probability, 0), COALESCE(gender_count, 0),
+			COALESCE(nationality, ''), COALESCE(nationality_probability, 0), COALESCE(nationality_count, 0),
+			enrichment_status, enriched_at, provenance`,
 		strings.Join(queryParts, ", "),
 		len(args),
 	)
 
-	stmt, err := s.db.PrepareContext(ctx, query)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Storage.Update: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("Storage.Update: %w", err)
 	}
 	defer stmt.Close()
 
-	if err = stmt.QueryRowContext(ctx, args...).
-		Scan(&p.Name, &p.Surname, &p.Patronymic, &p.Age, &p.Gender, &p.Nationality); err != nil {
+	if err = stmt.QueryRowContext(ctx, args...).Scan(personFields(p)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return fmt.Errorf("Storage.Update: %w", person.ErrNotFound)
 		}
@@ -138,42 +206,182 @@ func (s *Storage) Update(ctx context.Context, p *models.Person) error {
 		return fmt.Errorf("Storage.Update: %w", err)
 	}
 
+	if nationalities != nil {
+		p.Nationalities = nationalities
+		if err = replaceNationalities(ctx, tx, p); err != nil {
+			return fmt.Errorf("Storage.Update: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("Storage.Update: %w", err)
+	}
+
+	stored, err := s.nationalities(ctx, p.ID)
+	if err != nil {
+		return fmt.Errorf("Storage.Update: %w", err)
+	}
+	p.Nationalities = stored[p.ID]
+
 	return nil
 }
 
-// Create creates a new person.
+// Create creates a new person with the candidates of the nationality.
 //
-// The ID and CreatedOn must be filled by the database.
+// The ID and EnrichedAt must be filled by the database.
 func (s *Storage) Create(ctx context.Context, p *models.Person) error {
 	const query = `
 	INSERT INTO person
-		(name, surname, patronymic, age, gender, nationality)
+		(name, surname, patronymic, age, age_count, gender, gender_probability, gender_count,
+		nationality, nationality_probability, nationality_count, enrichment_status, provenance)
 	VALUES
-		($1, $2, $3, $4, $5, $6)
-	RETURNING id, name, surname, patronymic, age, gender, nationality
+		($1, $2, $3, NULLIF($4, 0), $5, NULLIF($6, ''), $7, $8, NULLIF($9, ''), $10, $11, $12, $13)
+	RETURNING id, enriched_at
 	`
 
-	stmt, err := s.db.PrepareContext(ctx, query)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Storage.Create: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("Storage.Create: %w", err)
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRowContext(ctx, p.Name, p.Surname, p.Patronymic, p.Age, p.Gender, p.Nationality).
-		Scan(&p.ID, &p.Name, &p.Surname, &p.Patronymic, &p.Age, &p.Gender, &p.Nationality)
+	err = stmt.QueryRowContext(ctx, p.Name, p.Surname, p.Patronymic,
+		p.Age, p.AgeCount,
+		p.Gender, p.GenderProbability, p.GenderCount,
+		p.Nationality, p.NationalityProbability, p.NationalityCount,
+		p.EnrichmentStatus, p.Provenance).
+		Scan(&p.ID, &p.EnrichedAt)
 	if err != nil {
 		return fmt.Errorf("Storage.Create: %w", err)
 	}
 
+	if err = createNationalities(ctx, tx, p); err != nil {
+		return fmt.Errorf("Storage.Create: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("Storage.Create: %w", err)
+	}
+
+	return nil
+}
+
+// personFields returns the destinations of the person columns
+// in the order of the select list of the person.
+func personFields(p *models.Person) []any {
+	return []any{
+		&p.ID, &p.Name, &p.Surname, &p.Patronymic,
+		&p.Age, &p.AgeCount,
+		&p.Gender, &p.GenderProbability, &p.GenderCount,
+		&p.Nationality, &p.NationalityProbability, &p.NationalityCount,
+		&p.EnrichmentStatus, &p.EnrichedAt, &p.Provenance,
+	}
+}
+
+// createNationalities inserts the candidates of the person nationality.
+func createNationalities(ctx context.Context, tx *sql.Tx, p *models.Person) error {
+	if len(p.Nationalities) == 0 {
+		return nil
+	}
+
+	const query = `
+	INSERT INTO person_nationality
+		(person_id, country_id, probability)
+	VALUES
+		($1, $2, $3)
+	ON CONFLICT (person_id, country_id) DO UPDATE SET probability = EXCLUDED.probability
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, n := range p.Nationalities {
+		if _, err = stmt.ExecContext(ctx, p.ID, n.CountryID, n.Probability); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// List returns a list of persons by given filter params.
+// replaceNationalities replaces the candidates of the person nationality.
+func replaceNationalities(ctx context.Context, tx *sql.Tx, p *models.Person) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM person_nationality WHERE person_id = $1", p.ID); err != nil {
+		return err
+	}
+
+	return createNationalities(ctx, tx, p)
+}
+
+// nationalities returns the candidates of the nationality of the people with given IDs.
+//
+// The candidates are grouped by the person ID and ordered by the probability descending.
+func (s *Storage) nationalities(ctx context.Context, ids ...string) (map[string][]models.Nationality, error) {
+	const query = `
+	SELECT
+		person_id,
+		country_id,
+		probability
+	FROM person_nationality
+	WHERE person_id = ANY($1)
+	ORDER BY person_id, probability DESC
+	`
+
+	result := make(map[string][]models.Nationality, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id string
+			n  models.Nationality
+		)
+		if err = rows.Scan(&id, &n.CountryID, &n.Probability); err != nil {
+			return nil, err
+		}
+		result[id] = append(result[id], n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// List returns the page of the people by given filter params
+// in the sort order of the filter, the ties are ordered by ID.
 //
-// If param have unsupported type returns storage.ErrUnsupportedParamType.
-// The param type can be int, float64, float32, uint, string.
-func (s *Storage) List(ctx context.Context, filter *models.Filter) ([]models.Person, error) {
-	query, args, err := filter.Query().
+// If the cursor of the filter is malformed returns models.ErrCursor.
+func (s *Storage) List(ctx context.Context, filter *models.Filter) (*models.Page, error) {
+	builder, err := filter.Query()
+	if err != nil {
+		return nil, fmt.Errorf("Storage.List: %w", err)
+	}
+
+	query, args, err := builder.
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -197,7 +405,7 @@ func (s *Storage) List(ctx context.Context, filter *models.Filter) ([]models.Per
 	var people []models.Person
 	for rows.Next() {
 		var p models.Person
-		if err = rows.Scan(&p.ID, &p.Name, &p.Surname, &p.Patronymic, &p.Age, &p.Gender, &p.Nationality); err != nil {
+		if err = rows.Scan(personFields(&p)...); err != nil {
 			return nil, fmt.Errorf("Storage.List: %w", err)
 		}
 		people = append(people, p)
@@ -207,6 +415,353 @@ func (s *Storage) List(ctx context.Context, filter *models.Filter) ([]models.Per
 		return nil, fmt.Errorf("Storage.List: %w", err)
 	}
 
+	page := filter.Page(people)
+
+	ids := make([]string, len(page.People))
+	for i := range page.People {
+		ids[i] = page.People[i].ID
+	}
+
+	nationalities, err := s.nationalities(ctx, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.List: %w", err)
+	}
+
+	for i := range page.People {
+		page.People[i].Nationalities = nationalities[page.People[i].ID]
+	}
+
+	return page, nil
+}
+
+// Count returns the count of all the people matching the filter regardless of the page.
+func (s *Storage) Count(ctx context.Context, filter *models.Filter) (int, error) {
+	query, args, err := filter.CountQuery().
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("Storage.Count: %w", err)
+	}
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("Storage.Count: %w", err)
+	}
+	defer stmt.Close()
+
+	var count int
+	if err = stmt.QueryRowContext(ctx, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("Storage.Count: %w", err)
+	}
+
+	return count, nil
+}
+
+// Stats returns the total count and the facets of the people matching the filter regardless of the page.
+func (s *Storage) Stats(ctx context.Context, filter *models.Filter) (*models.Stats, error) {
+	query, args, err := filter.StatsQuery().
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Stats: %w", err)
+	}
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Stats: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.Stats{Facets: models.Facets{
+		Gender:      []models.Facet{},
+		Nationality: []models.Facet{},
+		Age:         []models.Facet{},
+	}}
+	for rows.Next() {
+		var (
+			grouping, count int
+			value           string
+		)
+		if err = rows.Scan(&grouping, &value, &count); err != nil {
+			return nil, fmt.Errorf("Storage.Stats: %w", err)
+		}
+		stats.Add(grouping, value, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("Storage.Stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Statistics returns the age histogram, the age and the gender ratio of each nationality
+// and the count of the created people over time, they are read in one snapshot.
+func (s *Storage) Statistics(ctx context.Context, statistics *models.Statistics) (*models.StatisticsResult, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Statistics: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &models.StatisticsResult{
+		AgeHistogram:  []models.AgeBucket{},
+		Nationalities: []models.NationalityStatistics{},
+		Timeline:      []models.PeriodCount{},
+	}
+
+	if err = scan(ctx, tx, statistics.AgeQuery(), func(rows *sql.Rows) error {
+		var from, count int
+		if err := rows.Scan(&from, &count); err != nil {
+			return err
+		}
+
+		result.AgeHistogram = append(result.AgeHistogram, statistics.Bucket(from, count))
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("Storage.Statistics: %w", err)
+	}
+
+	if err = scan(ctx, tx, statistics.NationalityQuery(), func(rows *sql.Rows) error {
+		var n models.NationalityStatistics
+		if err := rows.Scan(&n.Nationality, &n.Count, &n.MeanAge, &n.MedianAge, &n.MaleRatio); err != nil {
+			return err
+		}
+
+		result.Nationalities = append(result.Nationalities, n)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("Storage.Statistics: %w", err)
+	}
+
+	if err = scan(ctx, tx, statistics.TimelineQuery(), func(rows *sql.Rows) error {
+		var p models.PeriodCount
+		if err := rows.Scan(&p.Period, &p.Count, &p.Total); err != nil {
+			return err
+		}
+
+		result.Timeline = append(result.Timeline, p)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("Storage.Statistics: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Storage.Statistics: %w", err)
+	}
+
+	return result, nil
+}
+
+// scan runs the query in the transaction and scans each of its rows.
+func scan(ctx context.Context, tx *sql.Tx, builder squirrel.SelectBuilder, row func(*sql.Rows) error) error {
+	query, args, err := builder.
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = row(rows); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Search returns the people found by the search ordered by their relevance.
+func (s *Storage) Search(ctx context.Context, search *models.Search) ([]models.SearchResult, error) {
+	query, args, err := search.Query().
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Search: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Search: %w", err)
+	}
+	defer tx.Rollback()
+
+	// the threshold of the word similarity operator selecting the candidates by the index
+	threshold := strconv.FormatFloat(search.Threshold(), 'f', -1, 64)
+	if _, err = tx.ExecContext(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`, threshold); err != nil {
+		return nil, fmt.Errorf("Storage.Search: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Search: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Search: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		var r models.SearchResult
+		if err = rows.Scan(append(personFields(&r.Person), &r.Score, &r.Distance)...); err != nil {
+			return nil, fmt.Errorf("Storage.Search: %w", err)
+		}
+		results = append(results, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("Storage.Search: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Storage.Search: %w", err)
+	}
+
+	ids := make([]string, len(results))
+	for i := range results {
+		ids[i] = results[i].Person.ID
+	}
+
+	nationalities, err := s.nationalities(ctx, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.Search: %w", err)
+	}
+
+	for i := range results {
+		results[i].Person.Nationalities = nationalities[results[i].Person.ID]
+	}
+
+	return results, nil
+}
+
+// ListPartial returns up to limit people failed to be enriched by any of the providers
+// and enriched before given time ordered by ID and starting after the person with given ID,
+// the empty ID starts from the first one.
+func (s *Storage) ListPartial(ctx context.Context, providers []string, before time.Time, after string, limit int) ([]models.Person, error) {
+	const query = `
+	SELECT
+		id,
+		name,
+		surname,
+		COALESCE(patronymic, ''),
+		COALESCE(age, 0),
+		COALESCE(age_count, 0),
+		COALESCE(gender, ''),
+		COALESCE(gender_probability, 0),
+		COALESCE(gender_count, 0),
+		COALESCE(nationality, ''),
+		COALESCE(nationality_probability, 0),
+		COALESCE(nationality_count, 0),
+		enrichment_status,
+		enriched_at,
+		provenance
+	FROM person
+	WHERE is_deleted = FALSE
+		AND enrichment_status ?| $1
+		AND enriched_at < $2
+		AND id > COALESCE(NULLIF($3, '')::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+	ORDER BY id
+	LIMIT $4
+	`
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.ListPartial: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(providers), before, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.ListPartial: %w", err)
+	}
+	defer rows.Close()
+
+	var people []models.Person
+	for rows.Next() {
+		var p models.Person
+		if err = rows.Scan(personFields(&p)...); err != nil {
+			return nil, fmt.Errorf("Storage.ListPartial: %w", err)
+		}
+		people = append(people, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("Storage.ListPartial: %w", err)
+	}
+
+	return people, nil
+}
+
+// ListStale returns up to limit people missing the age, gender or nationality
+// or enriched before given time ordered by ID and starting after the person with given ID,
+// the empty ID starts from the first one.
+func (s *Storage) ListStale(ctx context.Context, before time.Time, after string, limit int) ([]models.Person, error) {
+	const query = `
+	SELECT
+		id,
+		name,
+		surname,
+		COALESCE(patronymic, ''),
+		COALESCE(age, 0),
+		COALESCE(age_count, 0),
+		COALESCE(gender, ''),
+		COALESCE(gender_probability, 0),
+		COALESCE(gender_count, 0),
+		COALESCE(nationality, ''),
+		COALESCE(nationality_probability, 0),
+		COALESCE(nationality_count, 0),
+		enrichment_status,
+		enriched_at,
+		provenance
+	FROM person
+	WHERE is_deleted = FALSE
+		AND (age IS NULL OR gender IS NULL OR nationality IS NULL OR enriched_at < $1)
+		AND id > COALESCE(NULLIF($2, '')::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+	ORDER BY id
+	LIMIT $3
+	`
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.ListStale: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, before, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.ListStale: %w", err)
+	}
+	defer rows.Close()
+
+	var people []models.Person
+	for rows.Next() {
+		var p models.Person
+		if err = rows.Scan(personFields(&p)...); err != nil {
+			return nil, fmt.Errorf("Storage.ListStale: %w", err)
+		}
+		people = append(people, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("Storage.ListStale: %w", err)
+	}
+
 	return people, nil
 }
 
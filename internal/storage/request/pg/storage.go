@@ -0,0 +1,128 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/insan1a/exile/internal/models"
+	"github.com/insan1a/exile/internal/storage"
+	"github.com/insan1a/exile/internal/storage/request"
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+// New creates a new request storage.
+//
+// If db is nil returns storage.ErrNilDB.
+func New(db *sql.DB) (*Storage, error) {
+	if db == nil {
+		return nil, storage.ErrNilDB
+	}
+
+	return &Storage{db: db}, nil
+}
+
+// FindByID returns a request by given id.
+//
+// If request not found returns request.ErrNotFound.
+func (s *Storage) FindByID(ctx context.Context, id string) (*models.Request, error) {
+	const query = `
+	SELECT
+		id,
+		status,
+		COALESCE(person_id::text, ''),
+		COALESCE(error, ''),
+		created_at,
+		updated_at
+	FROM request
+	WHERE id = $1
+	`
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Storage.FindByID: %w", err)
+	}
+	defer stmt.Close()
+
+	var r models.Request
+	if err = stmt.QueryRowContext(ctx, id).
+		Scan(&r.ID, &r.Status, &r.PersonID, &r.Error, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("Storage.FindByID: %w", request.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("Storage.FindByID: %w", err)
+	}
+
+	return &r, nil
+}
+
+// Create creates a new request.
+//
+// If request is nil returns request.ErrNilRequest.
+func (s *Storage) Create(ctx context.Context, r *models.Request) error {
+	if r == nil {
+		return fmt.Errorf("Storage.Create: %w", request.ErrNilRequest)
+	}
+
+	const query = `
+	INSERT INTO request
+		(id, status)
+	VALUES
+		($1, $2)
+	RETURNING created_at, updated_at
+	`
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("Storage.Create: %w", err)
+	}
+	defer stmt.Close()
+
+	if err = stmt.QueryRowContext(ctx, r.ID, r.Status).Scan(&r.CreatedAt, &r.UpdatedAt); err != nil {
+		return fmt.Errorf("Storage.Create: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates the status, the person ID and the error of a request.
+//
+// If the request does not exist it is created, so the requests
+// produced bypassing the api are tracked as well.
+// If request is nil returns request.ErrNilRequest.
+func (s *Storage) Update(ctx context.Context, r *models.Request) error {
+	if r == nil {
+		return fmt.Errorf("Storage.Update: %w", request.ErrNilRequest)
+	}
+
+	const query = `
+	INSERT INTO request
+		(id, status, person_id, error)
+	VALUES
+		($1, $2, NULLIF($3, '')::uuid, NULLIF($4, ''))
+	ON CONFLICT (id) DO UPDATE SET
+		status = EXCLUDED.status,
+		person_id = EXCLUDED.person_id,
+		error = EXCLUDED.error,
+		updated_at = now()
+	RETURNING created_at, updated_at
+	`
+
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("Storage.Update: %w", err)
+	}
+	defer stmt.Close()
+
+	if err = stmt.QueryRowContext(ctx, r.ID, r.Status, r.PersonID, r.Error).
+		Scan(&r.CreatedAt, &r.UpdatedAt); err != nil {
+		return fmt.Errorf("Storage.Update: %w", err)
+	}
+
+	return nil
+}
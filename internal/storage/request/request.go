@@ -0,0 +1,20 @@
+package request
+
+import (
+	"context"
+	"errors"
+
+	"github.com/insan1a/exile/internal/models"
+)
+
+var (
+	ErrNotFound   = errors.New("the request not found")
+	ErrNilRequest = errors.New("the request could not be nil")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.20.2 --name Storage --output ./mocks --outpkg mocks
+type Storage interface {
+	FindByID(context.Context, string) (*models.Request, error)
+	Create(context.Context, *models.Request) error
+	Update(context.Context, *models.Request) error
+}
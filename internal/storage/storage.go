@@ -16,6 +16,7 @@ var (
 	ErrNilRedisClient       = errors.New("the redis client is nil")
 	ErrURLEmpty             = errors.New("the url is empty")
 	ErrUnsupportedParamType = errors.New("the query param have unsupported type")
+	ErrInvalidCapacity      = errors.New("the capacity must be positive")
 )
 
 // NewPostgresPool creates a new database connection pool for PostgreSQL.
@@ -11,3 +11,17 @@ docker compose exec broker \
     --bootstrap-server broker:9092 \
     --replication-factor 1 \
     --partitions 1
+
+docker compose exec broker \
+  kafka-topics --create \
+    --topic FIO_RESULT \
+    --bootstrap-server broker:9092 \
+    --replication-factor 1 \
+    --partitions 1
+
+docker compose exec broker \
+  kafka-topics --create \
+    --topic FIO_RETRY \
+    --bootstrap-server broker:9092 \
+    --replication-factor 1 \
+    --partitions 1